@@ -1,19 +1,30 @@
 package main
 
 import (
+	"embed"
+	"encoding/base64"
 	"errors"
 	"flag"
 	"fmt"
 	"html/template"
+	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"net/smtp"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	cloningprimer "github.com/DanielSchuette/cloningPrimer"
 )
 
+// embeddedFiles holds the templates and static files, so that the server can be started from any directory
+//
+//go:embed templates static
+var embeddedFiles embed.FS
+
 var (
 	err             error
 	tmpl            *template.Template
@@ -25,6 +36,11 @@ var (
 	}
 	local       = flag.Bool("local", false, "set this argument to `true' to run the server locally at 127.0.0.1:8080")
 	enabledSMTP = flag.Bool("smtp", true, "set this argument to `false' to run the server with SMTP disabled")
+	enzymeFile  = flag.String("enzyme_file", "", "optional file path to a *.re file that is served instead of the embedded `assets/enzymes.re'")
+	rebaseFile  = flag.String("rebase_file", "", "optional file path to a local copy of the REBASE database that is served instead of `assets/enzymes.re'")
+	rebaseFmt   = flag.String("rebase_format", cloningprimer.RebaseWithrefm, "format of the `--rebase_file' (one of `withrefm', `bairoch', `emboss')")
+	templateDir = flag.String("templates", "", "optional directory with templates that are used instead of the embedded `templates/'")
+	staticDir   = flag.String("static", "", "optional directory with static files that are served instead of the embedded `static/'")
 )
 
 // struct designForm is used by the server to hold data that was parsed from the
@@ -44,9 +60,23 @@ type designForm struct {
 	Enzymes              map[string]cloningprimer.RestrictEnzyme /* holds restriction enzyme information */
 	ForwardPrimer        string                                  /* holds the computed forward primer */
 	ReversePrimer        string                                  /* holds the computed reverse primer */
+	RecordName           string                                  /* name of the FASTA, GenBank or EMBL record if the user input was in one of these formats */
+	CDS                  string                                  /* gene name or locus tag of the CDS in a GenBank or EMBL record that primers are designed for */
+	CDSLocation          string                                  /* location of the selected CDS in the GenBank or EMBL record */
+	SoftMask             bool                                    /* true if lower case nucleotides are treated as soft-masked */
+	Fasta                string                                  /* holds the computed primers and PCR product in FASTA format */
+	Downloads            []download                              /* holds the annotated PCR product in GenBank and EMBL format */
+	Warnings             []string                                /* holds warnings about the computed primers (e.g. methylation) */
+	DigestPlan           []string                                /* holds the recommended double digest protocol */
 	Values               formValues                              /* holds data for forms to avoid hardcoded values */
 }
 
+// download holds a file that is offered for download on the results page
+type download struct {
+	Name string       /* file name, e.g. tp53.gb */
+	URL  template.URL /* data URL with the contents of the file */
+}
+
 // a struct that is used internally to avoid hardcoded form values (e.g. dropdown menues for
 // selecting from a range of integer values) `constants` (e.g. the range of allowed values
 // for primer overhang lengths) are server-side this way
@@ -61,15 +91,33 @@ type designPageContainer struct {
 	Values  formValues
 }
 
-func init() {
-	// parse templates
-	tmpl = template.Must(template.ParseGlob("templates/*"))
+func main() {
+	// parse command line flags
+	flag.Parse()
+
+	// log messages of the cloningprimer package (e.g. parsed enzymes) like all other server messages
+	cloningprimer.SetLogger(log.Default())
 
-	// parse `enzymes.re' and create map of restriction enzyme structs
-	enzymes, err = cloningprimer.ParseEnzymesFromFile("assets/enzymes.re")
+	// parse templates (embedded or from `--templates')
+	var templates fs.FS = embeddedFiles
+	templatePattern := "templates/*"
+	if *templateDir != "" {
+		templates, templatePattern = os.DirFS(*templateDir), "*"
+	}
+	tmpl = template.Must(template.ParseFS(templates, templatePattern))
+
+	// parse `enzymes.re' (or a local copy of REBASE) and create map of restriction enzyme structs
+	if *rebaseFile != "" {
+		enzymes, err = cloningprimer.ParseRebaseFromFile(*rebaseFile, *rebaseFmt)
+	} else if *enzymeFile != "" {
+		enzymes, err = cloningprimer.ParseEnzymesFromFile(*enzymeFile)
+	} else {
+		enzymes, err = cloningprimer.DefaultEnzymes()
+	}
 	if err != nil {
 		log.Fatalf("error loading enzymes: %v\n", err)
 	}
+	log.Printf("loaded %d enzyme(s)\n", len(enzymes))
 
 	// populate struct with data for the `design' template
 	// it must be package level because it is used in multiple handleFuncs
@@ -77,11 +125,6 @@ func init() {
 		Enzymes: enzymes,
 		Values:  formValueConsts,
 	}
-}
-
-func main() {
-	// parse command line flags
-	flag.Parse()
 
 	// get port
 	port := getPort()
@@ -104,17 +147,25 @@ func main() {
 	http.HandleFunc("/documentation/", documentationHandler)
 	http.HandleFunc("/enzymesPage/", enzymesHandler)
 	http.HandleFunc("/search/", enzymesSearchHandler)
+	http.HandleFunc("/enzymesDownload/", enzymesDownloadHandler)
 	http.HandleFunc("/designPage/", designHandler)
 	http.HandleFunc("/computePrimers/", computePrimersHandler)
 	http.HandleFunc("/links/", linksHandler)
 	http.HandleFunc("/license/", licenseHandler)
 	http.HandleFunc("/contribute/", contributeHandler)
 
-	// file server for static files
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
+	// file server for static files (embedded or from `--static')
+	static, err := fs.Sub(embeddedFiles, "static")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *staticDir != "" {
+		static = os.DirFS(*staticDir)
+	}
+	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(static))))
 
 	// listen and serve locally
-	err := http.ListenAndServe(port, nil)
+	err = http.ListenAndServe(port, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -182,6 +233,29 @@ func enzymesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func enzymesDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	// the format is passed as a query parameter, e.g. '/enzymesDownload/?format=csv'
+	format := r.URL.Query().Get("format")
+	contentTypes := map[string]string{
+		cloningprimer.ExportRE:   "text/plain; charset=utf-8",
+		cloningprimer.ExportJSON: "application/json",
+		cloningprimer.ExportCSV:  "text/csv; charset=utf-8",
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown download format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	// write the enzyme table as an attachment
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=\"enzymes."+format+"\"")
+	err := cloningprimer.WriteEnzymes(w, format, enzymes)
+	if err != nil {
+		log.Printf("error writing enzyme download: %v\n", err)
+	}
+}
+
 func enzymesSearchHandler(w http.ResponseWriter, r *http.Request) {
 	// parse request form and print query information on server site
 	r.ParseForm()
@@ -266,10 +340,47 @@ func computePrimersHandler(w http.ResponseWriter, r *http.Request) {
 	d.Enzymes = enzymes
 	d.Values = formValueConsts
 
-	// if any input was received, validate input sequence
-	d.Sequence, err = cloningprimer.ValidateSequence([]byte(d.Sequence))
+	// accept FASTA input (the first record is used, its sequence is validated below)
+	if text := strings.TrimSpace(strings.TrimPrefix(d.Sequence, "\ufeff")); strings.HasPrefix(text, ">") {
+		if fields := strings.Fields(text[1:]); len(fields) > 0 {
+			d.RecordName = fields[0]
+		}
+	}
+
+	// accept GenBank and EMBL input and design primers for exactly the requested CDS (if any)
+	format := cloningprimer.DetectFormat([]byte(d.Sequence))
+	if (format == cloningprimer.FormatGenBank) || (format == cloningprimer.FormatEMBL) || (d.CDS != "") {
+		err = selectCDS(&d)
+		if (err != nil) && (d.CDS == "") {
+			// copied records (e.g. from NCBI's web view) that are not well-formed are parsed leniently below
+			log.Printf("cannot parse GenBank or EMBL input, falling back to lenient parsing: %v\n", err)
+			err = nil
+		}
+		if err != nil {
+			log.Printf("error parsing GenBank or EMBL input: %v\n", err)
+			d.Sequence = fmt.Sprintf("an error occured: %v", err) /* show why the requested CDS cannot be used */
+
+			// return `designpage' template to user and return from handler
+			err = tmpl.ExecuteTemplate(w, "designcompute", d)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	// if any input was received, validate input sequence (position numbers, headers and formatting
+	// characters of copied text are ignored); soft-masked (lower case) nucleotides are preserved on request
+	var seq string
+	if d.SoftMask {
+		seq, err = cloningprimer.ParseSequenceLenient(strings.NewReader(d.Sequence))
+	} else {
+		seq, err = cloningprimer.ValidateSequenceLenient([]byte(d.Sequence))
+	}
+	d.Sequence = seq
 	if err != nil {
 		log.Printf("error validating user input sequence: %v\n", err)
+		d.Sequence = fmt.Sprintf("%s ... %v", seq, err) /* show the valid part of the sequence and the invalid letter */
 
 		// return `designpage' template to user and return from handler
 		err = tmpl.ExecuteTemplate(w, "designcompute", d)
@@ -333,8 +444,16 @@ func computePrimersHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	d.ForwardPrimer, err = cloningprimer.FindForward(d.Sequence, restrictF, regionF, compF, overhangF, startBool)
+	primersOK := true                         /* variable to keep track of whether both primers could be computed */
+	var maskF, maskR cloningprimer.MaskReport /* the complementary lengths are adjusted to avoid soft-masked nucleotides */
+	if d.SoftMask {
+		d.ForwardPrimer, maskF, err = cloningprimer.FindForwardMasked(d.Sequence, restrictF, regionF, compF, overhangF, startBool)
+		compF = maskF.Length
+	} else {
+		d.ForwardPrimer, err = cloningprimer.FindForward(d.Sequence, restrictF, regionF, compF, overhangF, startBool)
+	}
 	if err != nil {
+		primersOK = false
 		d.ForwardPrimer = fmt.Sprintf("an error occured: %v", err)
 		log.Printf("error calculating forward primer: %v\n", err)
 	}
@@ -361,12 +480,82 @@ func computePrimersHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	d.ReversePrimer, err = cloningprimer.FindReverse(d.Sequence, restrictR, regionR, compR, overhangR, stopBool)
+	if d.SoftMask {
+		d.ReversePrimer, maskR, err = cloningprimer.FindReverseMasked(d.Sequence, restrictR, regionR, compR, overhangR, stopBool)
+		compR = maskR.Length
+	} else {
+		d.ReversePrimer, err = cloningprimer.FindReverse(d.Sequence, restrictR, regionR, compR, overhangR, stopBool)
+	}
 	if err != nil {
+		primersOK = false
 		d.ReversePrimer = fmt.Sprintf("an error occured: %v", err)
 		log.Printf("error calculating reverse primer: %v\n", err)
 	}
 
+	// provide primers and PCR product in FASTA format
+	if product, err := cloningprimer.PCRProduct(d.Sequence, d.ForwardPrimer, d.ReversePrimer, regionF, compF, regionR, compR); primersOK && (err == nil) {
+		name := d.RecordName
+		if name == "" {
+			name = "sequence"
+		}
+		var sb strings.Builder
+		err = cloningprimer.WriteFasta(&sb, []cloningprimer.FastaRecord{
+			{Name: name + "_F", Description: fmt.Sprintf("forward primer (%s)", d.ForwardEnzyme), Sequence: d.ForwardPrimer},
+			{Name: name + "_R", Description: fmt.Sprintf("reverse primer (%s)", d.ReverseEnzyme), Sequence: d.ReversePrimer},
+			{Name: name + "_amplicon", Description: fmt.Sprintf("PCR product (%d bp)", len(product)), Sequence: product},
+		})
+		if err == nil {
+			d.Fasta = sb.String()
+		}
+
+		// provide the annotated PCR product as GenBank and EMBL file downloads
+		construct, err := cloningprimer.ConstructRecord(name, d.Sequence, cloningprimer.PrimerPair{
+			Forward: d.ForwardPrimer, Reverse: d.ReversePrimer, EnzymeF: enzymes[d.ForwardEnzyme], EnzymeR: enzymes[d.ReverseEnzyme],
+			StartF: regionF, LengthF: compF, StartR: regionR, LengthR: compR,
+		})
+		construct.Date = strings.ToUpper(time.Now().Format("02-Jan-2006"))
+		formats := []struct {
+			ext   string                                               /* file extension */
+			write func(io.Writer, []cloningprimer.GenBankRecord) error /* writer of the file format */
+		}{{".gb", cloningprimer.WriteGenBank}, {".embl", cloningprimer.WriteEMBL}}
+		for _, f := range formats {
+			sb.Reset()
+			if err == nil {
+				err = f.write(&sb, []cloningprimer.GenBankRecord{construct})
+			}
+			if err == nil {
+				d.Downloads = append(d.Downloads, download{Name: name + f.ext, URL: template.URL("data:text/plain;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(sb.String())))})
+			}
+		}
+		if err != nil {
+			log.Printf("error writing PCR product downloads: %v\n", err)
+		}
+	}
+
+	// warn if a primer had to be placed on soft-masked sequence
+	for _, msg := range []string{maskF.Message("forward"), maskR.Message("reverse")} {
+		if msg != "" {
+			d.Warnings = append(d.Warnings, msg)
+		}
+	}
+	if d.SoftMask {
+		d.ForwardComplementary, d.ReverseComplementary = strconv.Itoa(compF), strconv.Itoa(compR)
+	}
+
+	// warn if restriction sites in the primers or the template overlap Dam/Dcm methylation
+	pair := cloningprimer.PrimerPair{Forward: d.ForwardPrimer, Reverse: d.ReversePrimer, EnzymeF: enzymes[d.ForwardEnzyme], EnzymeR: enzymes[d.ReverseEnzyme]}
+	d.Warnings = append(d.Warnings, pair.MethylationWarnings(d.Sequence)...)
+
+	// recommend a buffer (or a sequential protocol) for the double digest
+	if d.ReverseEnzyme != d.ForwardEnzyme {
+		plan, err := cloningprimer.PlanDoubleDigest(enzymes[d.ForwardEnzyme], enzymes[d.ReverseEnzyme])
+		if err != nil {
+			d.DigestPlan = []string{fmt.Sprintf("cannot plan double digest: %v", err)}
+		} else {
+			d.DigestPlan = plan.Steps
+		}
+	}
+
 	// execute template with data
 	err = tmpl.ExecuteTemplate(w, "designcompute", d)
 	if err != nil {
@@ -407,6 +596,7 @@ func printDesignFormData(r *http.Request) {
 	log.Printf("/computePrimers/ r.Form['stopRadio']: %v\n", r.Form["stopRadio"])
 	log.Printf("/computePrimers/ r.Form['startRegion']: %v\n", r.Form["startRegion"])
 	log.Printf("/computePrimers/ r.Form['stopRegion']: %v\n", r.Form["stopRegion"])
+	log.Printf("/computePrimers/ r.Form['cdsQuery']: %v\n", r.Form["cdsQuery"])
 }
 
 func parseDesignFormData(r *http.Request) (designForm, error) {
@@ -431,10 +621,45 @@ func parseDesignFormData(r *http.Request) (designForm, error) {
 		Stop:                 r.Form["stopRadio"][0],
 		RegionF:              r.Form["startRegion"][0],
 		RegionR:              r.Form["stopRegion"][0],
+		CDS:                  strings.TrimSpace(r.FormValue("cdsQuery")), /* optional field */
+		SoftMask:             r.FormValue("softMask") == "yes",           /* optional checkbox */
 	}
 	return d, nil
 }
 
+// selectCDS replaces the GenBank or EMBL record in `d.Sequence' by its sequence; if `d.CDS' is set, the sequence
+// and the sub-region start positions are set such that primers are designed for exactly this CDS
+func selectCDS(d *designForm) error {
+	records, err := cloningprimer.ParseFeatureRecords(strings.NewReader(d.Sequence))
+	if err != nil {
+		return err
+	}
+	record := records[0]
+	if d.CDS == "" {
+		d.RecordName, d.Sequence = record.Name, record.Sequence
+		return nil
+	}
+
+	// use the first record that contains the CDS
+	var cds cloningprimer.Feature
+	for _, r := range records {
+		if cds, err = r.FindCDS(d.CDS); err == nil {
+			record = r
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	seq, regionF, regionR, err := cloningprimer.FeatureRegion(record.Sequence, cds)
+	if err != nil {
+		return err
+	}
+	d.RecordName, d.Sequence, d.CDSLocation = record.Name, seq, cds.Location
+	d.RegionF, d.RegionR = strconv.Itoa(regionF), strconv.Itoa(regionR)
+	return nil
+}
+
 // sendMail uses an SMTP server to send user input to an email address
 // all sensitive information (email address and password) is saved as environmental variables
 func sendMail(addr, pswd, host, port, msg string) error {
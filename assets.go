@@ -0,0 +1,33 @@
+package cloningprimer
+
+import (
+	// embed is required for the `go:embed' directives below
+	_ "embed"
+)
+
+// DefaultEnzymeFile and DefaultSequenceFile are the names under which the embedded default data is reported in user output
+const (
+	DefaultEnzymeFile   = "embedded:enzymes.re"
+	DefaultSequenceFile = "embedded:tp53.seq"
+)
+
+// defaultEnzymes holds the curated enzyme database (./app/assets/enzymes.re) that is compiled into the package
+//
+//go:embed app/assets/enzymes.re
+var defaultEnzymes []byte
+
+// defaultSequence holds the example tp53 sequence (./app/assets/tp53.seq) that is compiled into the package
+//
+//go:embed app/assets/tp53.seq
+var defaultSequence []byte
+
+// DefaultEnzymes parses the curated enzyme database that is compiled into the package, so that programs do not
+// depend on the location of ./app/assets/enzymes.re at runtime
+func DefaultEnzymes() (map[string]RestrictEnzyme, error) {
+	return parseEnzymes(defaultEnzymes, DefaultEnzymeFile)
+}
+
+// DefaultSequence returns the example tp53 sequence that is compiled into the package
+func DefaultSequence() (string, error) {
+	return parseSequence(defaultSequence, DefaultSequenceFile)
+}
@@ -0,0 +1,43 @@
+package cloningprimer
+
+import "testing"
+
+func TestDefaultEnzymes(t *testing.T) {
+	enzymes, err := DefaultEnzymes()
+	if err != nil {
+		t.Fatalf("DefaultEnzymes() == %v, want <nil>\n", err)
+	}
+	fromFile, err := ParseEnzymesFromFile("app/assets/enzymes.re")
+	if err != nil {
+		t.Fatalf("ParseEnzymesFromFile() == %v, want <nil>\n", err)
+	}
+	if !isSimilarMap(enzymes, fromFile) {
+		t.Errorf("DefaultEnzymes() returned %d enzymes, want the %d enzymes in app/assets/enzymes.re\n", len(enzymes), len(fromFile))
+	}
+
+	// the default enzymes have cut positions, except for nicking enzymes
+	for _, c := range []struct {
+		name     string
+		top      int
+		bottom   int
+		cutKnown bool
+	}{{"EcoRI", 1, 5, true}, {"KpnI", 5, 1, true}, {"BsaI", 7, 11, true}, {"BaeI", -10, -15, true}, {"Nt.BsmAI", 0, 0, false}} {
+		if e := enzymes[c.name]; (e.TopCut != c.top) || (e.BottomCut != c.bottom) || (e.CutKnown != c.cutKnown) {
+			t.Errorf("DefaultEnzymes() returned %s with cuts %d/%d (known: %v), want %d/%d (known: %v)\n", c.name, e.TopCut, e.BottomCut, e.CutKnown, c.top, c.bottom, c.cutKnown)
+		}
+	}
+}
+
+func TestDefaultSequence(t *testing.T) {
+	seq, err := DefaultSequence()
+	if err != nil {
+		t.Fatalf("DefaultSequence() == %v, want <nil>\n", err)
+	}
+	fromFile, err := ParseSequenceFromFile("app/assets/tp53.seq")
+	if err != nil {
+		t.Fatalf("ParseSequenceFromFile() == %v, want <nil>\n", err)
+	}
+	if seq != fromFile {
+		t.Errorf("DefaultSequence() == %s, want %s\n", seq, fromFile)
+	}
+}
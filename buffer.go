@@ -0,0 +1,185 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MinimumBufferActivity is the minimum activity (in % of the activity in the optimal buffer) that both enzymes of a
+// double digest must have in a buffer for it to be recommended for a simultaneous digest
+const MinimumBufferActivity = 75
+
+// bufferSaltRank orders the common NEB reaction buffers by salt concentration (sequential digests start with the
+// enzyme that prefers the buffer with less salt, so that salt can be added for the second enzyme)
+var bufferSaltRank = map[string]int{"r1.1": 1, "rCutSmart": 2, "r2.1": 3, "r3.1": 4}
+
+// DigestPlan holds the recommended protocol for a double digest with two restriction enzymes
+type DigestPlan struct {
+	Buffer     string   /* buffer for a simultaneous digest (empty if the digest must be sequential) */
+	Activities [2]int   /* activities (%) of the first and second enzyme in `Buffer' */
+	Sequential bool     /* true if the enzymes cannot be used at the same time */
+	Steps      []string /* human readable protocol */
+	Warnings   []string /* sites in the digested DNA that overlap Dam or Dcm methylation (see `CheckMethylation') */
+}
+
+// String returns the protocol of a `DigestPlan' as a single string
+func (p DigestPlan) String() string {
+	return strings.Join(p.Steps, "\n")
+}
+
+// PlanDoubleDigest recommends a single buffer in which both enzymes `first' and `second' have at least
+// `MinimumBufferActivity' percent activity (the buffer with the highest minimal activity is chosen, see `bufferPreference'
+// for ties) or, if no such buffer
+// exists or the incubation temperatures of the enzymes differ, a sequential digest protocol; the sites of both enzymes
+// in the digested `dna' (e.g. a PCR product and a vector) that overlap Dam or Dcm methylation are added to the
+// warnings of the plan; an error is returned if buffer activity data is missing for one of the enzymes
+func PlanDoubleDigest(first, second RestrictEnzyme, dna ...FastaRecord) (DigestPlan, error) {
+	for _, e := range []RestrictEnzyme{first, second} {
+		if len(e.BufferActivity) == 0 {
+			return DigestPlan{}, fmt.Errorf("no buffer activity data available for %s", e.Name)
+		}
+	}
+	var warnings []string
+	for _, r := range dna {
+		warnings = append(warnings, methylationWarnings(r.Name, r.Sequence, first, second)...)
+	}
+
+	// find the common buffer in which the less active enzyme is most active (ties are broken by the combined activity
+	// and then by the preference of the buffer)
+	var plan DigestPlan
+	best, bestSum, bestPref := -1, -1, -1
+	for _, buffer := range sortedBuffers(first.BufferActivity) {
+		a, ok := second.BufferActivity[buffer]
+		if !ok {
+			continue
+		}
+		low, sum, pref := first.BufferActivity[buffer], first.BufferActivity[buffer]+a, bufferPreference(buffer, first, second)
+		if a < low {
+			low = a
+		}
+		if (low > best) || ((low == best) && ((sum > bestSum) || ((sum == bestSum) && (pref > bestPref)))) {
+			best, bestSum, bestPref = low, sum, pref
+			plan.Buffer = buffer
+			plan.Activities = [2]int{first.BufferActivity[buffer], a}
+		}
+	}
+	sameTemp := (first.IncubationTemp == 0) || (second.IncubationTemp == 0) || (first.IncubationTemp == second.IncubationTemp)
+	if (best >= MinimumBufferActivity) && sameTemp {
+		plan.Steps = []string{fmt.Sprintf("digest with %s (%d%%) and %s (%d%%) simultaneously in %s%s", first.Name, plan.Activities[0], second.Name, plan.Activities[1], plan.Buffer, temperatureSuffix(first.IncubationTemp, second.IncubationTemp))}
+		plan.Warnings = warnings
+		return plan, nil
+	}
+
+	// a common buffer exists but the enzymes need different temperatures: digest sequentially in the same buffer,
+	// starting with the enzyme that has the lower incubation temperature
+	if best >= MinimumBufferActivity {
+		low, high := first, second
+		if low.IncubationTemp > high.IncubationTemp {
+			low, high = high, low
+		}
+		plan.Sequential = true
+		plan.Steps = []string{
+			fmt.Sprintf("1. digest with %s in %s at %d°C", low.Name, plan.Buffer, low.IncubationTemp),
+			fmt.Sprintf("2. add %s to the same reaction and incubate at %d°C", high.Name, high.IncubationTemp),
+		}
+		plan.Warnings = warnings
+		return plan, nil
+	}
+
+	// no common buffer: start with the enzyme that prefers less salt, then purify the DNA before the second digest
+	bufferA, bufferB := bestBuffer(first), bestBuffer(second)
+	a, b := first, second
+	if bufferSaltRank[bufferB] < bufferSaltRank[bufferA] {
+		a, b = second, first
+		bufferA, bufferB = bufferB, bufferA
+	}
+	plan = DigestPlan{Sequential: true, Warnings: warnings}
+	plan.Steps = []string{
+		fmt.Sprintf("1. digest with %s in %s%s", a.Name, bufferA, temperatureSuffix(a.IncubationTemp, 0)),
+		"2. purify the DNA (e.g. with a spin column)",
+		fmt.Sprintf("3. digest with %s in %s%s", b.Name, bufferB, temperatureSuffix(b.IncubationTemp, 0)),
+	}
+	return plan, nil
+}
+
+// ParseBufferActivity parses buffer activities in the *.re notation "buffer:percent,..." (e.g. "r2.1:100,rCutSmart:50")
+func ParseBufferActivity(s string) (map[string]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	activities := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected 'buffer:percent', not %q", item)
+		}
+		a, err := strconv.Atoi(strings.TrimSuffix(parts[1], "%"))
+		if (err != nil) || (a < 0) || (a > 100) {
+			return nil, fmt.Errorf("expected an activity between 0 and 100 percent, not %q", parts[1])
+		}
+		activities[parts[0]] = a
+	}
+	return activities, nil
+}
+
+// FormatBufferActivity formats buffer activities in the *.re notation that is parsed by `ParseBufferActivity'
+func FormatBufferActivity(activities map[string]int) string {
+	var items []string
+	for _, buffer := range sortedBuffers(activities) {
+		items = append(items, fmt.Sprintf("%s:%d", buffer, activities[buffer]))
+	}
+	return strings.Join(items, ",")
+}
+
+// bestBuffer returns the recommended buffer of `e' or, if there is none, the buffer with the highest activity
+func bestBuffer(e RestrictEnzyme) string {
+	if e.Buffer != "" {
+		return e.Buffer
+	}
+	var best string
+	for _, buffer := range sortedBuffers(e.BufferActivity) {
+		if (best == "") || (e.BufferActivity[buffer] > e.BufferActivity[best]) {
+			best = buffer
+		}
+	}
+	return best
+}
+
+// bufferPreference ranks a `buffer' among buffers in which the `enzymes' are equally active: buffers that are
+// recommended by more enzymes come first and rCutSmart, which most NEB enzymes are supplied with, is preferred over the
+// other buffers (remaining ties are resolved alphabetically)
+func bufferPreference(buffer string, enzymes ...RestrictEnzyme) int {
+	pref := 0
+	for _, e := range enzymes {
+		if e.Buffer == buffer {
+			pref += 2
+		}
+	}
+	if buffer == "rCutSmart" {
+		pref++
+	}
+	return pref
+}
+
+// sortedBuffers returns the buffer names in `activities' in alphabetical order (to make results deterministic)
+func sortedBuffers(activities map[string]int) []string {
+	buffers := make([]string, 0, len(activities))
+	for buffer := range activities {
+		buffers = append(buffers, buffer)
+	}
+	sort.Strings(buffers)
+	return buffers
+}
+
+// temperatureSuffix returns " at X°C" for the first known temperature of `t1' and `t2' or an empty string
+func temperatureSuffix(t1, t2 int) string {
+	switch {
+	case t1 != 0:
+		return fmt.Sprintf(" at %d°C", t1)
+	case t2 != 0:
+		return fmt.Sprintf(" at %d°C", t2)
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testCaseDigest struct {
+	in   [2]RestrictEnzyme
+	want DigestPlan
+	err  error
+}
+
+type testCaseBufferActivity struct {
+	in   string
+	want string
+	err  error
+}
+
+func TestPlanDoubleDigest(t *testing.T) {
+	ecoRI := RestrictEnzyme{Name: "EcoRI", Buffer: "rCutSmart", IncubationTemp: 37, BufferActivity: map[string]int{"r1.1": 25, "r2.1": 100, "r3.1": 50, "rCutSmart": 100}}
+	bamHI := RestrictEnzyme{Name: "BamHI", Buffer: "r3.1", IncubationTemp: 37, BufferActivity: map[string]int{"r1.1": 75, "r2.1": 100, "r3.1": 100, "rCutSmart": 100}}
+	hindIII := RestrictEnzyme{Name: "HindIII", Buffer: "r2.1", IncubationTemp: 37, BufferActivity: map[string]int{"r1.1": 25, "r2.1": 100, "r3.1": 50, "rCutSmart": 100}}
+	kpnI := RestrictEnzyme{Name: "KpnI", Buffer: "r1.1", IncubationTemp: 37, BufferActivity: map[string]int{"r1.1": 100, "r2.1": 75, "r3.1": 0, "rCutSmart": 75}}
+	salI := RestrictEnzyme{Name: "SalI", Buffer: "r3.1", IncubationTemp: 37, BufferActivity: map[string]int{"r1.1": 0, "r2.1": 10, "r3.1": 100, "rCutSmart": 10}}
+	smaI := RestrictEnzyme{Name: "SmaI", Buffer: "rCutSmart", IncubationTemp: 25, BufferActivity: map[string]int{"r1.1": 0, "r2.1": 50, "r3.1": 0, "rCutSmart": 100}}
+	cases := []testCaseDigest{
+		// common buffers with full activity for both enzymes (ties are resolved by the recommended buffers and rCutSmart)
+		{
+			in: [2]RestrictEnzyme{ecoRI, bamHI},
+			want: DigestPlan{Buffer: "rCutSmart", Activities: [2]int{100, 100}, Steps: []string{
+				"digest with EcoRI (100%) and BamHI (100%) simultaneously in rCutSmart at 37°C",
+			}},
+			err: nil,
+		},
+		{
+			in: [2]RestrictEnzyme{bamHI, hindIII},
+			want: DigestPlan{Buffer: "r2.1", Activities: [2]int{100, 100}, Steps: []string{
+				"digest with BamHI (100%) and HindIII (100%) simultaneously in r2.1 at 37°C",
+			}},
+			err: nil,
+		},
+		{
+			in: [2]RestrictEnzyme{{Name: "BamHI", BufferActivity: bamHI.BufferActivity}, {Name: "HindIII", BufferActivity: hindIII.BufferActivity}},
+			want: DigestPlan{Buffer: "rCutSmart", Activities: [2]int{100, 100}, Steps: []string{
+				"digest with BamHI (100%) and HindIII (100%) simultaneously in rCutSmart",
+			}},
+			err: nil,
+		},
+		// no common buffer, the enzyme that prefers less salt is used first
+		{
+			in: [2]RestrictEnzyme{salI, kpnI},
+			want: DigestPlan{Sequential: true, Steps: []string{
+				"1. digest with KpnI in r1.1 at 37°C",
+				"2. purify the DNA (e.g. with a spin column)",
+				"3. digest with SalI in r3.1 at 37°C",
+			}},
+			err: nil,
+		},
+		// common buffer but different incubation temperatures
+		{
+			in: [2]RestrictEnzyme{ecoRI, smaI},
+			want: DigestPlan{Buffer: "rCutSmart", Activities: [2]int{100, 100}, Sequential: true, Steps: []string{
+				"1. digest with SmaI in rCutSmart at 25°C",
+				"2. add EcoRI to the same reaction and incubate at 37°C",
+			}},
+			err: nil,
+		},
+		// missing buffer data
+		{
+			in:   [2]RestrictEnzyme{ecoRI, {Name: "AclI"}},
+			want: DigestPlan{},
+			err:  errors.New("no buffer activity data available for AclI"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := PlanDoubleDigest(c.in[0], c.in[1])
+
+		// test similarity of expected and received value
+		if (got.Buffer != c.want.Buffer) || (got.Activities != c.want.Activities) || (got.Sequential != c.want.Sequential) || (got.String() != c.want.String()) {
+			t.Errorf("PlanDoubleDigest(%v, %v) == %+v, want %+v\n", c.in[0].Name, c.in[1].Name, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("PlanDoubleDigest(%v, %v) == %v, want %v\n", c.in[0].Name, c.in[1].Name, err, c.err)
+		}
+	}
+}
+
+func TestPlanDoubleDigestMethylation(t *testing.T) {
+	// sites of both enzymes in the digested DNA that overlap methylation are reported
+	xbaI := RestrictEnzyme{Name: "XbaI", RecognitionSite: "TCTAGA", IncubationTemp: 37, BufferActivity: map[string]int{"rCutSmart": 100}, Dam: MethylationBlocked}
+	ecoRI := RestrictEnzyme{Name: "EcoRI", RecognitionSite: "GAATTC", IncubationTemp: 37, BufferActivity: map[string]int{"rCutSmart": 100}}
+	plan, err := PlanDoubleDigest(ecoRI, xbaI, FastaRecord{Name: "insert", Sequence: "GAATTCAAATCTAGATC"}, FastaRecord{Name: "vector", Sequence: "GAATTCTCTAGAAA"})
+	want := []string{"insert: XbaI site at position 10 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain"}
+	if (err != nil) || !reflect.DeepEqual(plan.Warnings, want) {
+		t.Errorf("PlanDoubleDigest() == %q, %v, want %q, <nil>\n", plan.Warnings, err, want)
+	}
+}
+
+func TestParseBufferActivity(t *testing.T) {
+	cases := []testCaseBufferActivity{
+		{
+			in:   "rCutSmart:100, r2.1:50%",
+			want: "r2.1:50,rCutSmart:100",
+			err:  nil,
+		},
+		{
+			in:   "",
+			want: "",
+			err:  nil,
+		},
+		{
+			in:   "r2.1",
+			want: "",
+			err:  errors.New("expected 'buffer:percent', not \"r2.1\""),
+		},
+		{
+			in:   "r2.1:150",
+			want: "",
+			err:  errors.New("expected an activity between 0 and 100 percent, not \"150\""),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		activities, err := ParseBufferActivity(c.in)
+		if got := FormatBufferActivity(activities); got != c.want {
+			t.Errorf("ParseBufferActivity(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseBufferActivity(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
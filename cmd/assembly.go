@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// printAssemblyPrimers prints the `primers' of an assembly (the tails that do not bind to the template in lower case)
+func printAssemblyPrimers(primers []cloningprimer.AssemblyPrimer) {
+	color.Set(color.FgYellow, color.Bold) /* make output colorful */
+	fmt.Println("computing primers...")
+	color.Unset() /* unset colorful output */
+	for _, p := range primers {
+		fmt.Printf("%s (%d nucleotides, %d nucleotides tail, Tm of annealing part %.1f°C)\n", p.Name, len(p.Sequence), p.Tail, p.Tm)
+		color.Set(color.FgGreen, color.Bold) /* make output colorful */
+		fmt.Printf("result: %s%s\n", strings.ToLower(p.Sequence[:p.Tail]), p.Sequence[p.Tail:])
+		color.Unset() /* unset colorful ouput */
+	}
+}
+
+// printJunctions prints the overlaps of the `junctions' of an assembly
+func printJunctions(junctions []cloningprimer.AssemblyJunction) {
+	fmt.Println("----------------------------------------------------------------------\nOverlaps:")
+	for _, j := range junctions {
+		fmt.Printf("%s/%s: %s (%d nucleotides, Tm %.1f°C, unique: %v, longest hairpin stem: %d)\n", j.Left, j.Right, j.Overlap, len(j.Overlap), j.Tm, j.Unique, j.HairpinStem)
+	}
+}
+
+// printWarnings prints the `warnings' of a design
+func printWarnings(warnings []string) {
+	if len(warnings) > 0 {
+		color.Set(color.FgRed, color.Bold)
+		for _, w := range warnings {
+			fmt.Printf("warning: %s\n", w)
+		}
+		color.Unset()
+	}
+}
+
+// writeAssembly writes the `primers' and the assembled `product' to the requested '--fasta_out' and '--out' files
+func writeAssembly(primers []cloningprimer.AssemblyPrimer, product cloningprimer.GenBankRecord) {
+	// write primers and assembled product to a FASTA file if requested
+	if *fastaOut != "" {
+		var records []cloningprimer.FastaRecord
+		for _, p := range primers {
+			records = append(records, cloningprimer.FastaRecord{Name: p.Name, Description: fmt.Sprintf("%s primer (%d nucleotides tail)", *mode, p.Tail), Sequence: p.Sequence})
+		}
+		records = append(records, cloningprimer.FastaRecord{Name: product.Name, Description: fmt.Sprintf("assembled product (%d bp, %s)", len(product.Sequence), product.Topology), Sequence: product.Sequence})
+		if err := cloningprimer.WriteFastaToFile(*fastaOut, records); err != nil {
+			log.Fatalf("error writing FASTA file: %v\n", err)
+		}
+		color.Set(color.FgGreen) /* make output colorful */
+		fmt.Printf("wrote primers and assembled product to '%s'\n", *fastaOut)
+		color.Unset() /* unset colorful output */
+	}
+
+	// write the annotated product to a GenBank or EMBL file if requested
+	if *outFile != "" {
+		if err := writeConstruct(*outFile, product); err != nil {
+			log.Fatalf("error writing construct file: %v\n", err)
+		}
+		color.Set(color.FgGreen) /* make output colorful */
+		fmt.Printf("wrote annotated product to '%s'\n", *outFile)
+		color.Unset() /* unset colorful output */
+	}
+}
+
+// selectRecord returns the '--record' from `records' (or the first record if none is requested)
+func selectRecord(records []cloningprimer.FastaRecord) cloningprimer.FastaRecord {
+	if *recordName == "" {
+		return records[0]
+	}
+	for _, r := range records {
+		if r.Name == *recordName {
+			return r
+		}
+	}
+	color.Set(color.FgRed) /* make output colorful */
+	log.Fatalf("invalid input: cannot find record %v in '%s'\n", *recordName, *seqFile)
+	color.Unset() /* unset colorful output */
+	return cloningprimer.FastaRecord{}
+}
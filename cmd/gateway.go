@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designGateway designs attB primers for the ORF of the selected record and prints them; if a '--donor' vector is
+// given, the entry clone of the BP reaction is reported (and written to the output files instead of the PCR product)
+func designGateway(records []cloningprimer.FastaRecord) {
+	orf := selectRecord(records)
+	opts := cloningprimer.GatewayOptions{Kozak: *kozak, ShineDalgarno: *shineDalgar}
+	switch strings.ToUpper(*fusion) {
+	case "NONE":
+	case "N":
+		opts.NTerminalFusion = true
+	case "C":
+		opts.CTerminalFusion = true
+	case "NC":
+		opts.NTerminalFusion, opts.CTerminalFusion = true, true
+	default:
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: unknown fusion %q (must be one of 'none', 'N', 'C', 'NC')\n", *fusion)
+		color.Unset() /* unset colorful output */
+	}
+	design, err := cloningprimer.DesignGateway(orf, opts)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing Gateway primers: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	printAssemblyPrimers(design.Primers)
+	fmt.Printf("attB PCR product: %d nucleotides\n", len(design.Product))
+	printWarnings(design.Warnings)
+	if *donorFile == "" {
+		writeAssembly(design.Primers, design.Record(design.ORF.Name+"_attB"))
+		return
+	}
+
+	// simulate the BP reaction with the donor vector
+	color.Set(color.FgGreen) /* make output colorful */
+	donors, err := cloningprimer.ParseSequenceRecordsFromFile(*donorFile)
+	color.Unset() /* unset colorful output */
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while loading donor vector file: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	entry, err := design.EntryClone(design.ORF.Name+"_entry", donors[0].Sequence)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while simulating BP recombination: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("entry clone after BP recombination with %v: %d bp\n", donors[0].Name, len(entry.Sequence))
+	color.Unset() /* unset colorful output */
+	writeAssembly(design.Primers, entry)
+}
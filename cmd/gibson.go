@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designGibson designs primers for a Gibson assembly of the `--vector' and the `inserts' and prints them
+func designGibson(inserts []cloningprimer.FastaRecord, enzymes map[string]cloningprimer.RestrictEnzyme) {
+	vector := readVector()
+	opts := cloningprimer.GibsonOptions{MinOverlap: *overlapMin, MaxOverlap: *overlapMax, OverlapTm: *overlapTm}
+	if *vectorEnz != "" {
+		opts.Enzymes = vectorEnzymes(enzymes)
+	}
+	assembly, err := cloningprimer.DesignGibson(vector, inserts, opts)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing Gibson assembly: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	if *vectorEnz != "" {
+		color.Set(color.FgYellow) /* make output colorful */
+		fmt.Printf("linearized vector %v with %v (backbone of %d nucleotides)\n", vector.Name, *vectorEnz, len(assembly.Fragments[0].Sequence))
+		color.Unset() /* unset colorful output */
+	}
+	printAssembly(assembly)
+}
+
+// readVector returns the first record of the `--vector' file
+func readVector() cloningprimer.FastaRecord {
+	if *vectorFile == "" {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: '--mode %s' requires a '--vector'\n", *mode)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgGreen) /* make output colorful */
+	records, err := cloningprimer.ParseSequenceRecordsFromFile(*vectorFile)
+	color.Unset() /* unset colorful output */
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while loading vector file: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	vector := records[0]
+	if vector.Name == "" {
+		vector.Name = "vector"
+	}
+	return vector
+}
+
+// vectorEnzymes returns the `--vector_enzymes' from `enzymes'
+func vectorEnzymes(enzymes map[string]cloningprimer.RestrictEnzyme) []cloningprimer.RestrictEnzyme {
+	var selected []cloningprimer.RestrictEnzyme
+	for _, name := range strings.Split(*vectorEnz, ",") {
+		selected = append(selected, lookupEnzyme(enzymes, strings.TrimSpace(name)))
+	}
+	return selected
+}
+
+// printAssembly prints the primers, overlaps and warnings of `assembly' and writes the requested output files
+func printAssembly(assembly cloningprimer.Assembly) {
+	printAssemblyPrimers(assembly.Primers)
+	printJunctions(assembly.Junctions)
+	printWarnings(assembly.Warnings)
+	name := assembly.Fragments[0].Name + "_" + assembly.Fragments[1].Name
+	writeAssembly(assembly.Primers, assembly.Record(name))
+}
+
+// lookupEnzyme returns the enzyme `name' from `enzymes'; an exact name wins over partial matches (e.g. EcoRI vs.
+// EcoRII in REBASE), otherwise `name' must match exactly one enzyme
+func lookupEnzyme(enzymes map[string]cloningprimer.RestrictEnzyme, name string) cloningprimer.RestrictEnzyme {
+	if e, ok := enzymes[name]; ok {
+		return e
+	}
+	matches, err := cloningprimer.FilterEnzymeMap(enzymes, name)
+	if (err != nil) || (len(matches) != 1) {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: %v does not match exactly one enzyme in '%s'\n", name, *enzymeFile)
+		color.Unset() /* unset colorful output */
+	}
+	var e cloningprimer.RestrictEnzyme
+	for _, m := range matches {
+		e = m
+	}
+	return e
+}
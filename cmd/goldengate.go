@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designGoldenGate designs primers for a Golden Gate assembly of the `parts' with the '--type2s_enzyme' and prints them
+func designGoldenGate(parts []cloningprimer.FastaRecord, enzymes map[string]cloningprimer.RestrictEnzyme) {
+	enzyme := lookupEnzyme(enzymes, *typeIISEnz)
+	var fusions []string
+	if *fusionSites != "" {
+		fusions = strings.Split(strings.ReplaceAll(*fusionSites, " ", ""), ",")
+	}
+	assembly, err := cloningprimer.DesignGoldenGate(parts, cloningprimer.GoldenGateOptions{Enzyme: enzyme, Fusions: fusions})
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing Golden Gate assembly: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("using %v as the Type IIS enzyme (recognition sequence: %v)\n", enzyme.Name, enzyme.RecognitionSite)
+	color.Unset() /* unset colorful output */
+	printAssemblyPrimers(assembly.Primers)
+	fmt.Println("----------------------------------------------------------------------\nFusion sites:")
+	for i, f := range assembly.Fusions {
+		label := "end of assembly"
+		if i < len(assembly.Parts) {
+			label = "in front of " + assembly.Parts[i].Name
+		}
+		fmt.Printf("%s at position %d (%s)\n", f, assembly.Positions[i], label)
+	}
+	printWarnings(assembly.Warnings)
+	writeAssembly(assembly.Primers, assembly.Record(parts[0].Name+"_assembly"))
+}
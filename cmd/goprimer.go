@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	cloningprimer "github.com/DanielSchuette/cloningPrimer"
 	"github.com/fatih/color"
 )
 
 var (
-	seqFile     = flag.String("seq_file", "../app/assets/tp53.seq", "valid file path to a *.seq file with correctly formatted DNA sequence information\ndefault is the file at 'github.com/DanielSchuette/app/assets/tp53.seq'")
-	enzymeFile  = flag.String("enzyme_file", "../app/assets/enzymes.re", "valid file path to a *.re file with correctly formatted restriction enzyme information\ndefault is the file at 'github.com/DanielSchuette/app/assets/enzymes.re'")
+	seqFile     = flag.String("seq_file", "", "valid file path to a *.seq, FASTA, GenBank, EMBL or SnapGene (*.dna) file with correctly formatted DNA sequence information ('-' reads the sequence from stdin)\ndefaults to the tp53 sequence that is compiled into the binary (see 'github.com/DanielSchuette/app/assets/tp53.seq')")
+	enzymeFile  = flag.String("enzyme_file", "", "valid file path to a *.re file with correctly formatted restriction enzyme information ('-' reads *.re or REBASE data from stdin)\ndefaults to the enzyme database that is compiled into the binary (see 'github.com/DanielSchuette/app/assets/enzymes.re')")
+	rebaseFile  = flag.String("rebase_file", "", "optional file path to a local copy of the REBASE database that is used instead of the '--enzyme_file'\nfor the emboss format, this is the emboss_e.### file (an emboss_r.### file in the same directory is used automatically)")
+	rebaseFmt   = flag.String("rebase_format", cloningprimer.RebaseWithrefm, "format of the '--rebase_file' (one of 'withrefm', 'bairoch', 'emboss')")
+	exportFile  = flag.String("export_enzymes", "", "optional file path; if set, the loaded enzymes are exported to this file and no primers are computed")
+	exportFmt   = flag.String("export_format", "", "format of the '--export_enzymes' file (one of 're', 'json', 'csv'), defaults to the file extension")
 	enzymeNameF = flag.String("enzyme_name_forward", "BamHI", "name of the enzyme you want to use for the 5' end (must be in the '--enzyme_file')")
 	enzymeNameR = flag.String("enzyme_name_reverse", "EcoRI", "name of the enzyme you want to use for the 3' end (must be in the '--enzyme_file')")
 	startPos    = flag.Int("5prime_start", 1, "5' position of the first complementary nucleotide in the provided sequence that the forward primer should bind to\nsee './doc' for more information on how to customize primer calculations")
@@ -23,6 +30,40 @@ var (
 	lengthR     = flag.Int("length_reverse", 18, "length of the complementary part of the reverse primer")
 	startCodon  = flag.Bool("start_codon", true, "set this flag to 'false' if the input sequence does not have a start codon (an ATG will be added automatically)")
 	stopCodon   = flag.Bool("stop_codon", true, "set this flag to 'false' if the input sequence does not have a stop cdon (then, a TAA will be added automatically)")
+	recordName  = flag.String("record", "", "name of the FASTA record in the '--seq_file' that primers are designed for (defaults to the first record)")
+	cdsQuery    = flag.String("cds", "", "gene name or locus tag of a CDS feature in the GenBank, EMBL or SnapGene '--seq_file'; if set, primers are designed for exactly this CDS\n'--5prime_start' and '--3prime_start' are ignored then")
+	fastaOut    = flag.String("fasta_out", "", "optional file path; if set, the primers and the PCR product are written to this FASTA file")
+	outFile     = flag.String("out", "", "optional file path; if set, the PCR product is written to this GenBank (or EMBL, if the file ends in '.embl') file with annotated primers, restriction sites and added codons")
+	lenient     = flag.Bool("lenient", false, "parse the '--seq_file' leniently: ignore position numbers, header lines (e.g. 'ORIGIN') and formatting characters of copied text\ninvalid letters are still reported with their line and column")
+	softMask    = flag.Bool("soft_mask", false, "treat lower case nucleotides of the *.seq or FASTA '--seq_file' as soft-masked (e.g. repeats in genome FASTA files)\nthe complementary lengths are adjusted to keep the annealing regions (especially their 3' ends) off masked nucleotides")
+	mode        = flag.String("mode", "restriction", "cloning method: 'restriction' (restriction enzyme cloning), 'gibson' (Gibson or NEBuilder HiFi assembly of the '--vector' and all records of the '--seq_file' as inserts)\n'golden_gate' (Golden Gate assembly of all records of the '--seq_file' as parts) 'silent' (removal of internal sites from a coding sequence by silent mutations), 'gateway' (attB primers for Gateway cloning of an ORF)\n'in_fusion' and 'slic' (ligation independent cloning of all records of the '--seq_file' into the '--vector') 'mutagenesis' (site-directed mutagenesis of a plasmid), 'scan' (alanine or saturation scanning mutagenesis of a plasmid)\n'truncation' (restriction cloning primers of N- and C-terminal truncations of a coding sequence)\n'soe' (fusion of all records of the '--seq_file' by overlap extension PCR) or 'recombineering' (homology arm primers for lambda Red or yeast recombination)")
+	vectorFile  = flag.String("vector", "", "file path to the vector (*.seq, FASTA, GenBank, EMBL or SnapGene file) of an assembly ('--mode gibson', 'in_fusion' or 'slic'), the first record is used")
+	vectorEnz   = flag.String("vector_enzymes", "", "comma separated names of one or two enzymes that linearize the '--vector' (inserts are placed between their sites)\nif empty, the '--vector' is used as an already linearized backbone")
+	overlapMin  = flag.Int("overlap_min", cloningprimer.GibsonMinOverlap, "minimum length of the overlaps between the fragments of an assembly ('--mode soe' defaults to 18)")
+	overlapMax  = flag.Int("overlap_max", cloningprimer.GibsonMaxOverlap, "maximum length of the overlaps between the fragments of an assembly ('--mode soe' defaults to 30)")
+	overlapTm   = flag.Float64("overlap_tm", cloningprimer.GibsonOverlapTm, "minimum Tm (in °C, nearest neighbor method) of the overlaps between the fragments of an assembly ('--mode soe' defaults to 55)")
+	typeIISEnz  = flag.String("type2s_enzyme", "BsaI", "name of the Type IIS enzyme of a Golden Gate assembly (e.g. BsaI, BsmBI or BbsI)")
+	fusionSites = flag.String("fusions", "", "comma separated 4-nt fusion sites of a Golden Gate assembly, one in front of every part and one behind the last part\nempty sites (e.g. 'GGAG,,,CGCT') are chosen automatically from the junctions of the parts")
+	removeSites = flag.String("remove_sites", "", "comma separated names of the enzymes whose sites are removed by silent mutations ('--mode silent'), defaults to the '--type2s_enzyme'")
+	frameStart  = flag.Int("frame", 1, "position of the first nucleotide of the reading frame of the coding sequence ('--mode silent', 'mutagenesis', 'scan' and 'truncation')")
+	fusion      = flag.String("fusion", "none", "fusion of the ORF with tags of the Gateway destination vector ('--mode gateway'): 'none', 'N' (in frame with attB1), 'C' (in frame with attB2, without stop codon) or 'NC'")
+	kozak       = flag.Bool("kozak", false, "add a Kozak sequence in front of the start codon ('--mode gateway')")
+	shineDalgar = flag.Bool("shine_dalgarno", false, "add a Shine-Dalgarno sequence in front of the start codon ('--mode gateway')")
+	donorFile   = flag.String("donor", "", "optional file path to a donor vector with attP1 and attP2 sites (e.g. pDONR221); if set, the entry clone of the BP reaction is reported and written to the output files ('--mode gateway')")
+	insertAt    = flag.Int("insert_at", 0, "position of the '--vector' behind which the inserts are placed if the vector is linearized by inverse PCR ('--mode in_fusion' or 'slic')\nor of the locus in the '--seq_file' behind which the '--cassette' is placed ('--mode recombineering')")
+	deleteLen   = flag.Int("delete", 0, "number of nucleotides behind '--insert_at' that are deleted by the inverse PCR or replaced by the '--cassette'")
+	homology    = flag.Int("homology", 0, "length of the homology arms of an In-Fusion or SLIC assembly (defaults to 15 for '--mode in_fusion' and 25 for '--mode slic')\nor of the primers of '--mode recombineering' (defaults to 50)")
+	mutation    = flag.String("mutation", "", "mutation that is introduced into the plasmid in the '--seq_file' ('--mode mutagenesis'), e.g. 523A>G, R175H (codons are counted from '--frame'),\n123_125del, 123_124insGGC or 123_125delinsAT")
+	alanineScan = flag.String("alanine_scan", "", "range of codons (e.g. 10-50, counted from '--frame') that are replaced by alanine (alanine by glycine) one after another ('--mode scan')")
+	saturate    = flag.String("saturate", "", "comma separated codons (counted from '--frame') that are replaced by the '--degenerate_codon' one after another ('--mode scan')")
+	degenerate  = flag.String("degenerate_codon", "NNK", "degenerate codon of a saturation scan: 'NNK', 'NNS' or 'NNN' ('--mode scan')")
+	scanPrimers = flag.String("scan_primers", "both", "primers of a scan that are written to the oligo table: 'both', 'quikchange' or 'q5' ('--mode scan')")
+	oligoTable  = flag.String("oligo_table", "", "optional file path; if set, the oligo order table of a scan is written to this CSV file instead of stdout ('--mode scan')")
+	truncStarts = flag.String("truncation_starts", "", "comma separated first codons (counted from '--frame') of the constructs of a truncation series ('--mode truncation'), 1 keeps the N-terminus")
+	truncEnds   = flag.String("truncation_ends", "", "comma separated last codons (counted from '--frame') of the constructs of a truncation series ('--mode truncation')")
+	matrixOut   = flag.String("matrix_out", "", "optional file path; if set, the matrix of the primer pairs of a truncation series and their constructs is written to this CSV file instead of stdout")
+	linkers     = flag.String("linkers", "", "comma separated linker sequences that are inserted between the records of the '--seq_file' ('--mode soe'), one per junction\nempty linkers (e.g. 'GGTGGCGGTGGCTCT,') fuse the records directly")
+	markerFile  = flag.String("cassette", "", "file path to the selection cassette (*.seq, FASTA, GenBank, EMBL or SnapGene file) that replaces part of the locus ('--mode recombineering'), the first record is used")
 	verbose     = flag.Bool("verbose", false, "enable verbose output (defaults to false)")
 )
 
@@ -30,22 +71,30 @@ func main() {
 	// parse command line arguments
 	flag.Parse()
 
-	// check if `seqFile' and `enzymeFile' arguments are provided
-	if (*seqFile == "") || (*enzymeFile == "") {
-		color.Set(color.FgRed) /* make output colorful */
-		fmt.Println("arguments `--seq_file' and `--enzyme_file' are required (see `--help' for more information)")
-		color.Unset() /* unset colorful output */
-		os.Exit(1)
-	}
+	// report parsed files on stdout (the package does not print anything by default)
+	cloningprimer.SetLogger(log.New(os.Stdout, "", 0))
 
-	// load *.re file
+	// load *.re file or, if requested, a local copy of REBASE
 	color.Set(color.FgGreen) /* make output colorful */
-	enzymes, err := cloningprimer.ParseEnzymesFromFile(*enzymeFile)
+	var enzymes map[string]cloningprimer.RestrictEnzyme
+	var err error
+	if *rebaseFile != "" {
+		enzymes, err = cloningprimer.ParseRebaseFromFile(*rebaseFile, *rebaseFmt)
+		*enzymeFile = *rebaseFile /* used in error messages below */
+		fmt.Printf("parsed %d enzyme(s) from '%s'\n", len(enzymes), *rebaseFile)
+	} else if *enzymeFile == "-" {
+		enzymes, err = cloningprimer.ParseEnzymes(os.Stdin)
+		fmt.Printf("parsed %d enzyme(s) from stdin\n", len(enzymes))
+	} else if *enzymeFile != "" {
+		enzymes, err = cloningprimer.ParseEnzymesFromFile(*enzymeFile)
+	} else {
+		enzymes, err = cloningprimer.DefaultEnzymes()
+		*enzymeFile = cloningprimer.DefaultEnzymeFile /* used in error messages below */
+	}
 	color.Unset() /* unset colorful output */
-	color.Unset()
 	if err != nil {
 		color.Set(color.FgRed) /* make output colorful */
-		log.Fatalf("error while loading *.re file: %v\n", err)
+		log.Fatalf("error while loading enzyme file: %v\n", err)
 		color.Unset() /* unset colorful output */
 	}
 	if *verbose {
@@ -54,13 +103,153 @@ func main() {
 		color.Unset() /* unset colorful output */
 	}
 
-	// load *.seq file
+	// export enzymes and exit if requested
+	if *exportFile != "" {
+		err = cloningprimer.ExportEnzymesToFile(*exportFile, *exportFmt, enzymes)
+		if err != nil {
+			color.Set(color.FgRed) /* make output colorful */
+			log.Fatalf("error while exporting enzymes: %v\n", err)
+			color.Unset() /* unset colorful output */
+		}
+		color.Set(color.FgGreen) /* make output colorful */
+		fmt.Printf("exported %d enzyme(s) to '%s'\n", len(enzymes), *exportFile)
+		color.Unset() /* unset colorful output */
+		return
+	}
+
+	// load *.seq, FASTA, GenBank, EMBL or SnapGene file and select the requested record
 	color.Set(color.FgGreen) /* make output colorful */
-	seq, err := cloningprimer.ParseSequenceFromFile(*seqFile)
+	var records []cloningprimer.FastaRecord
+	var gbRecords []cloningprimer.GenBankRecord
+	switch {
+	case *cdsQuery != "":
+		if *seqFile == "-" {
+			gbRecords, err = cloningprimer.ParseFeatureRecords(os.Stdin)
+		} else {
+			gbRecords, err = cloningprimer.ParseFeatureRecordsFromFile(*seqFile)
+		}
+		for _, r := range gbRecords {
+			records = append(records, cloningprimer.FastaRecord{Name: r.Name, Description: r.Definition, Sequence: r.Sequence})
+		}
+	case *lenient && (*seqFile != ""):
+		// copied text with position numbers, headers or formatting characters (the record is named after the file)
+		var seq, name string
+		if *seqFile == "-" {
+			seq, err = cloningprimer.ParseSequenceLenient(os.Stdin)
+		} else {
+			seq, err = cloningprimer.ParseSequenceLenientFromFile(*seqFile)
+			name = strings.TrimSuffix(filepath.Base(*seqFile), filepath.Ext(*seqFile))
+		}
+		records = []cloningprimer.FastaRecord{{Name: name, Sequence: seq}}
+	case *seqFile == "-":
+		records, err = cloningprimer.ParseSequenceRecords(os.Stdin)
+	case *seqFile == "":
+		var seq string
+		seq, err = cloningprimer.DefaultSequence()
+		records = []cloningprimer.FastaRecord{{Name: "tp53", Sequence: seq}}
+	default:
+		records, err = cloningprimer.ParseSequenceRecordsFromFile(*seqFile)
+	}
 	color.Unset() /* unset colorful output */
 	if err != nil {
 		color.Set(color.FgRed) /* make output colorful */
-		log.Fatalf("error while loading *.seq file: %v\n", err)
+		log.Fatalf("error while loading sequence file: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+
+	// design primers for an assembly instead of restriction cloning if requested
+	switch *mode {
+	case "restriction":
+	case "gibson":
+		designGibson(records, enzymes)
+		return
+	case "golden_gate":
+		designGoldenGate(records, enzymes)
+		return
+	case "silent":
+		planSilentMutations(records, enzymes)
+		return
+	case "gateway":
+		designGateway(records)
+		return
+	case "in_fusion", "slic":
+		designInFusion(records, enzymes)
+		return
+	case "mutagenesis":
+		designMutagenesis(records)
+		return
+	case "scan":
+		designScan(records)
+		return
+	case "truncation":
+		designTruncations(records, enzymes)
+		return
+	case "soe":
+		designSOE(records)
+		return
+	case "recombineering":
+		designRecombineering(records)
+		return
+	default:
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: unknown mode %q (must be one of 'restriction', 'gibson', 'golden_gate', 'silent', 'gateway', 'in_fusion', 'slic', 'mutagenesis', 'scan', 'truncation', 'soe', 'recombineering')\n", *mode)
+		color.Unset() /* unset colorful output */
+	}
+	record := records[0]
+	var cds cloningprimer.Feature
+	if *cdsQuery != "" {
+		// select the requested record (or the first record that contains the CDS)
+		record.Name = ""
+		err = fmt.Errorf("invalid input: cannot find record %v in '%s'", *recordName, *seqFile)
+		if *recordName == "" {
+			err = fmt.Errorf("invalid input: no CDS with gene name or locus tag %q in '%s'", *cdsQuery, *seqFile)
+		}
+		for i, r := range gbRecords {
+			if (*recordName != "") && (r.Name != *recordName) {
+				continue
+			}
+			cds, err = r.FindCDS(*cdsQuery)
+			if (err == nil) || (*recordName != "") {
+				record = records[i]
+				break
+			}
+		}
+		if err != nil {
+			color.Set(color.FgRed) /* make output colorful */
+			log.Fatalf("error while selecting CDS: %v\n", err)
+			color.Unset() /* unset colorful output */
+		}
+	} else if *recordName != "" {
+		record.Name = ""
+		for _, r := range records {
+			if r.Name == *recordName {
+				record = r
+				break
+			}
+		}
+		if record.Name == "" {
+			color.Set(color.FgRed) /* make output colorful */
+			log.Fatalf("invalid input: cannot find record %v in '%s'\n", *recordName, *seqFile)
+			color.Unset() /* unset colorful output */
+		}
+	}
+	if record.Name == "" {
+		record.Name = "sequence" /* *.seq data from stdin has no name */
+	}
+	seq := record.Sequence
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("using record %v (%d nucleotides)\n", record.Name, len(seq))
+	color.Unset() /* unset colorful output */
+	if *cdsQuery != "" {
+		// design primers for exactly the selected CDS (on its coding strand)
+		seq, *startPos, *stopPos, err = cloningprimer.FeatureRegion(seq, cds)
+		if err != nil {
+			color.Set(color.FgRed) /* make output colorful */
+			log.Fatalf("error while selecting CDS: %v\n", err)
+			color.Unset() /* unset colorful output */
+		}
+		color.Set(color.FgYellow) /* make output colorful */
+		fmt.Printf("using CDS %v at %v (5' start %d, 3' start %d)\n", cds.Label(), cds.Location, *startPos, *stopPos)
 		color.Unset() /* unset colorful output */
 	}
 	if *verbose {
@@ -71,14 +260,19 @@ func main() {
 
 	// get forward and reverse primer recognition sequences from the `enzymes' map using regular expression matching
 	// report an error if no or more then one enzyme was matched; forward primer:
-	var enzymeF string /* variable to hold the 5' enzyme */
-	var enzymeR string /* variable to hold the 3' enzyme */
+	var enzymeF string                         /* variable to hold the 5' enzyme recognition sequence */
+	var enzymeR string                         /* variable to hold the 3' enzyme recognition sequence */
+	var selectedF cloningprimer.RestrictEnzyme /* variable to hold the 5' enzyme */
+	var selectedR cloningprimer.RestrictEnzyme /* variable to hold the 3' enzyme */
 	enzymeFMap, err := cloningprimer.FilterEnzymeMap(enzymes, *enzymeNameF)
 	if err != nil {
 		color.Set(color.FgRed) /* make output colorful */
 		log.Fatalf("error filtering enzyme map: %v\n", err)
 		color.Unset() /* unset colorful output */
 	}
+	if e, ok := enzymes[*enzymeNameF]; ok { /* an exact match wins (e.g. EcoRI vs. EcoRII in REBASE) */
+		enzymeFMap = map[string]cloningprimer.RestrictEnzyme{e.Name: e}
+	}
 	if len(enzymeFMap) < 1 {
 		color.Set(color.FgRed) /* make output colorful */
 		log.Fatalf("invalid input: cannot find %v in '%s'\n", *enzymeNameF, *enzymeFile)
@@ -94,6 +288,7 @@ func main() {
 		fmt.Printf("using %v as the 5' restriction enzyme (recognition sequence: %v)\n", k, v.RecognitionSite)
 		color.Unset() /* unset colorful output */
 		enzymeF = v.RecognitionSite
+		selectedF = v
 	}
 
 	// reverse primer:
@@ -103,6 +298,9 @@ func main() {
 		log.Fatalf("error filtering enzyme map: %v\n", err)
 		color.Unset() /* unset colorful output */
 	}
+	if e, ok := enzymes[*enzymeNameR]; ok { /* an exact match wins (e.g. EcoRI vs. EcoRII in REBASE) */
+		enzymeRMap = map[string]cloningprimer.RestrictEnzyme{e.Name: e}
+	}
 	if len(enzymeRMap) < 1 {
 		color.Set(color.FgRed) /* make output colorful */
 		log.Fatalf("invalid input: cannot find %v in '%s'\n", *enzymeNameR, *enzymeFile)
@@ -118,16 +316,30 @@ func main() {
 		fmt.Printf("using %v as the 3' restriction enzyme (recognition sequence: %v)\n", k, v.RecognitionSite)
 		color.Unset() /* unset colorful output */
 		enzymeR = v.RecognitionSite
+		selectedR = v
 	}
 
 	// calculate primers based upon `seq', `enzymeF', and `enzymeR'
-	primerF, err := cloningprimer.FindForward(seq, enzymeF, *startPos, *lengthF, *overhangF, *startCodon)
+	// if `softMask' is set, the complementary lengths are adjusted to avoid soft-masked nucleotides
+	var primerF, primerR string
+	var maskF, maskR cloningprimer.MaskReport
+	if *softMask {
+		primerF, maskF, err = cloningprimer.FindForwardMasked(seq, enzymeF, *startPos, *lengthF, *overhangF, *startCodon)
+		*lengthF = maskF.Length
+	} else {
+		primerF, err = cloningprimer.FindForward(seq, enzymeF, *startPos, *lengthF, *overhangF, *startCodon)
+	}
 	if err != nil {
 		color.Set(color.FgRed) /* make output colorful */
 		log.Fatalf("error while computing forward primer: %v\n", err)
 		color.Unset() /* unset colorful output */
 	}
-	primerR, err := cloningprimer.FindReverse(seq, enzymeR, *stopPos, *lengthR, *overhangR, *stopCodon)
+	if *softMask {
+		primerR, maskR, err = cloningprimer.FindReverseMasked(seq, enzymeR, *stopPos, *lengthR, *overhangR, *stopCodon)
+		*lengthR = maskR.Length
+	} else {
+		primerR, err = cloningprimer.FindReverse(seq, enzymeR, *stopPos, *lengthR, *overhangR, *stopCodon)
+	}
 	if err != nil {
 		color.Set(color.FgRed) /* make output colorful */
 		log.Fatalf("error while computing reverse primer: %v\n", err)
@@ -146,6 +358,71 @@ func main() {
 	color.Set(color.FgGreen, color.Bold) /* make output colorful */
 	fmt.Printf("result: %s\n", primerR)
 	color.Unset() /* unset colorful ouput */
+	for _, msg := range []string{maskF.Message("forward"), maskR.Message("reverse")} {
+		if msg != "" {
+			color.Set(color.FgRed) /* make output colorful */
+			fmt.Printf("warning: %s\n", msg)
+			color.Unset() /* unset colorful output */
+		}
+	}
+
+	// check whether the restriction sites in the primers or the template overlap Dam/Dcm methylation
+	pair := cloningprimer.PrimerPair{
+		Forward: primerF, Reverse: primerR, EnzymeF: selectedF, EnzymeR: selectedR,
+		StartF: *startPos, LengthF: *lengthF, StartR: *stopPos, LengthR: *lengthR,
+	}
+	if warnings := pair.MethylationWarnings(seq); len(warnings) > 0 {
+		fmt.Println("----------------------------------------------------------------------\nMethylation:")
+		color.Set(color.FgRed, color.Bold)
+		for _, w := range warnings {
+			fmt.Printf("warning: %s\n", w)
+		}
+		color.Unset()
+	}
+
+	// recommend a buffer (or a sequential protocol) for the double digest
+	if selectedF.Name != selectedR.Name {
+		plan, err := cloningprimer.PlanDoubleDigest(selectedF, selectedR)
+		fmt.Println("----------------------------------------------------------------------\nDouble Digest:")
+		if err != nil {
+			fmt.Printf("cannot plan double digest: %v\n", err)
+		} else {
+			fmt.Println(plan)
+		}
+	}
+
+	// write primers and PCR product to a FASTA file if requested
+	if *fastaOut != "" {
+		product, err := cloningprimer.PCRProduct(seq, primerF, primerR, *startPos, *lengthF, *stopPos, *lengthR)
+		if err != nil {
+			log.Fatalf("error computing PCR product: %v\n", err)
+		}
+		err = cloningprimer.WriteFastaToFile(*fastaOut, []cloningprimer.FastaRecord{
+			{Name: record.Name + "_F", Description: fmt.Sprintf("forward primer (%s)", selectedF.Name), Sequence: primerF},
+			{Name: record.Name + "_R", Description: fmt.Sprintf("reverse primer (%s)", selectedR.Name), Sequence: primerR},
+			{Name: record.Name + "_amplicon", Description: fmt.Sprintf("PCR product (%d bp)", len(product)), Sequence: product},
+		})
+		if err != nil {
+			log.Fatalf("error writing FASTA file: %v\n", err)
+		}
+		color.Set(color.FgGreen) /* make output colorful */
+		fmt.Printf("wrote primers and PCR product to '%s'\n", *fastaOut)
+		color.Unset() /* unset colorful output */
+	}
+
+	// write the annotated PCR product to a GenBank or EMBL file if requested
+	if *outFile != "" {
+		construct, err := cloningprimer.ConstructRecord(record.Name, seq, pair)
+		if err != nil {
+			log.Fatalf("error annotating PCR product: %v\n", err)
+		}
+		if err = writeConstruct(*outFile, construct); err != nil {
+			log.Fatalf("error writing construct file: %v\n", err)
+		}
+		color.Set(color.FgGreen) /* make output colorful */
+		fmt.Printf("wrote annotated PCR product to '%s'\n", *outFile)
+		color.Unset() /* unset colorful output */
+	}
 
 	// calculate 'GC' content of forward and reverse primer
 	fmt.Println("----------------------------------------------------------------------\nStatistics:")
@@ -176,3 +453,12 @@ func main() {
 	fmt.Printf("Tm of complementary part of reverse primer: %v\n", tmR)
 	color.Unset()
 }
+
+// writeConstruct writes `construct' to `file' in GenBank format (or EMBL format, if `file' ends in '.embl')
+func writeConstruct(file string, construct cloningprimer.GenBankRecord) error {
+	construct.Date = strings.ToUpper(time.Now().Format("02-Jan-2006"))
+	if strings.HasSuffix(file, ".embl") {
+		return cloningprimer.WriteEMBLToFile(file, []cloningprimer.GenBankRecord{construct})
+	}
+	return cloningprimer.WriteGenBankToFile(file, []cloningprimer.GenBankRecord{construct})
+}
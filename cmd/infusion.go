@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designInFusion designs primers for an In-Fusion or SLIC assembly of the `--vector' and the `inserts' and prints them;
+// the vector is linearized with the `--vector_enzymes' or by inverse PCR behind the `--insert_at' position
+func designInFusion(inserts []cloningprimer.FastaRecord, enzymes map[string]cloningprimer.RestrictEnzyme) {
+	vector := readVector()
+	opts := cloningprimer.InFusionOptions{InsertAt: *insertAt, Delete: *deleteLen, Homology: *homology}
+	if *vectorEnz != "" {
+		opts.Enzymes = vectorEnzymes(enzymes)
+	}
+	if (opts.Homology == 0) && (*mode == "slic") {
+		opts.Homology = cloningprimer.SLICHomology
+	}
+	assembly, err := cloningprimer.DesignInFusion(vector, inserts, opts)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing %s assembly: %v\n", *mode, err)
+		color.Unset() /* unset colorful output */
+	}
+	printAssembly(assembly)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designMutagenesis designs QuikChange and Q5 site-directed mutagenesis primers that introduce the '--mutation' into
+// the selected record and prints them
+func designMutagenesis(records []cloningprimer.FastaRecord) {
+	template := selectRecord(records)
+	if *mutation == "" {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: '--mode mutagenesis' requires a '--mutation'\n")
+		color.Unset() /* unset colorful output */
+	}
+	design, err := cloningprimer.DesignMutagenesis(template, *mutation, *frameStart)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing mutagenesis primers: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("introducing %v into %v\n", design.Mutation, template.Name)
+	color.Unset() /* unset colorful output */
+
+	// the back-to-back Q5 primers show inserted nucleotides in lower case (substitutions are centred in the forward primer)
+	printAssemblyPrimers(design.Q5)
+	fmt.Println("----------------------------------------------------------------------\nQuikChange primers (complementary, the mutation is in the middle):")
+	for _, p := range design.QuikChange {
+		fmt.Printf("%s (%d nucleotides, Tm %.1f°C)\n", p.Name, len(p.Sequence), p.Tm)
+		color.Set(color.FgGreen, color.Bold) /* make output colorful */
+		fmt.Printf("result: %s\n", p.Sequence)
+		color.Unset() /* unset colorful ouput */
+	}
+	printWarnings(design.Warnings)
+	writeAssembly(append(design.QuikChange, design.Q5...), design.Record(template.Name+"_mutant"))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designRecombineering designs primers that amplify the '--cassette' with homology arms to the selected locus, such
+// that recombination replaces the '--delete' nucleotides behind '--insert_at' with the cassette, and prints them
+func designRecombineering(records []cloningprimer.FastaRecord) {
+	locus := selectRecord(records)
+	cassette := readCassette()
+	design, err := cloningprimer.DesignRecombineering(locus, cassette, cloningprimer.RecombineeringOptions{InsertAt: *insertAt, Delete: *deleteLen, Homology: *homology})
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing recombineering primers: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("replacing %d nucleotide(s) of %v behind position %d with %v (%d nucleotides)\n", *deleteLen, design.Locus.Name, *insertAt, design.Cassette.Name, len(design.Cassette.Sequence))
+	color.Unset() /* unset colorful output */
+
+	// the homology arms are the tails of the primers and are shown in lower case
+	printAssemblyPrimers(design.Primers)
+	fmt.Printf("----------------------------------------------------------------------\nPCR product: %d nucleotides\n", len(design.Product))
+	printWarnings(design.Warnings)
+	writeAssembly(design.Primers, design.Record(design.Locus.Name+"_"+design.Cassette.Name))
+}
+
+// readCassette returns the first record of the '--cassette' file
+func readCassette() cloningprimer.FastaRecord {
+	if *markerFile == "" {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: '--mode recombineering' requires a '--cassette'\n")
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgGreen) /* make output colorful */
+	records, err := cloningprimer.ParseSequenceRecordsFromFile(*markerFile)
+	color.Unset() /* unset colorful output */
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while loading cassette file: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	return records[0]
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designScan designs mutagenesis primers for an alanine scan of the '--alanine_scan' codon range or a saturation scan
+// of the '--saturate' codons of the selected record and writes them as an oligo order table
+func designScan(records []cloningprimer.FastaRecord) {
+	template := selectRecord(records)
+	var scan cloningprimer.MutagenesisScan
+	var err error
+	switch {
+	case (*alanineScan == "") == (*saturate == ""):
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: '--mode scan' requires either an '--alanine_scan' range or '--saturate' codons\n")
+		color.Unset() /* unset colorful output */
+	case *alanineScan != "":
+		bounds := strings.SplitN(*alanineScan, "-", 2)
+		first, errFirst := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		last, errLast := first, error(nil)
+		if len(bounds) == 2 {
+			last, errLast = strconv.Atoi(strings.TrimSpace(bounds[1]))
+		}
+		if (errFirst != nil) || (errLast != nil) {
+			color.Set(color.FgRed) /* make output colorful */
+			log.Fatalf("invalid input: cannot parse codon range %q (expected e.g. 10-50)\n", *alanineScan)
+			color.Unset() /* unset colorful output */
+		}
+		scan, err = cloningprimer.AlanineScan(template, *frameStart, first, last)
+	default:
+		scan, err = cloningprimer.SaturationScan(template, *frameStart, parseCodons("saturate", *saturate), *degenerate)
+	}
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing scan primers: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	var quikChange, q5 bool
+	switch *scanPrimers {
+	case "both":
+		quikChange, q5 = true, true
+	case "quikchange":
+		quikChange = true
+	case "q5":
+		q5 = true
+	default:
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: unknown '--scan_primers' %q (must be one of 'both', 'quikchange' or 'q5')\n", *scanPrimers)
+		color.Unset() /* unset colorful output */
+	}
+
+	// report every mutation with the warnings of its primers
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("designing %d mutation(s) of %v (reading frame starts at position %d)\n", len(scan.Designs), template.Name, *frameStart)
+	color.Unset() /* unset colorful output */
+	for _, d := range scan.Designs {
+		fmt.Printf("%v\n", d.Mutation)
+		printWarnings(d.Warnings)
+	}
+	printWarnings(scan.Warnings)
+
+	// write the oligo order table to a file if requested, otherwise to stdout
+	if *oligoTable == "" {
+		fmt.Println("----------------------------------------------------------------------\nOligo order table:")
+		if err := scan.WriteOligoTable(os.Stdout, quikChange, q5); err != nil {
+			log.Fatalf("error writing oligo table: %v\n", err)
+		}
+		return
+	}
+	f, err := os.Create(*oligoTable)
+	if err != nil {
+		log.Fatalf("error writing oligo table: %v\n", err)
+	}
+	defer f.Close()
+	if err := scan.WriteOligoTable(f, quikChange, q5); err != nil {
+		log.Fatalf("error writing oligo table: %v\n", err)
+	}
+	color.Set(color.FgGreen) /* make output colorful */
+	fmt.Printf("wrote oligo order table to '%s'\n", *oligoTable)
+	color.Unset() /* unset colorful output */
+}
+
+// parseCodons parses the comma separated codon numbers `value' of the command line flag `name'
+func parseCodons(name, value string) []int {
+	var codons []int
+	for _, item := range strings.Split(value, ",") {
+		c, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			color.Set(color.FgRed) /* make output colorful */
+			log.Fatalf("invalid input: cannot parse codon %q of '--%s'\n", item, name)
+			color.Unset() /* unset colorful output */
+		}
+		codons = append(codons, c)
+	}
+	return codons
+}
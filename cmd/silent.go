@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// planSilentMutations removes the sites of the '--remove_sites' enzymes (or the '--type2s_enzyme') from the coding
+// sequence of the selected record by silent mutations and prints the primers that introduce them
+func planSilentMutations(records []cloningprimer.FastaRecord, enzymes map[string]cloningprimer.RestrictEnzyme) {
+	record := selectRecord(records)
+	names := *removeSites
+	if names == "" {
+		names = *typeIISEnz
+	}
+	var selected []cloningprimer.RestrictEnzyme
+	for _, name := range strings.Split(names, ",") {
+		selected = append(selected, lookupEnzyme(enzymes, strings.TrimSpace(name)))
+	}
+	plan, err := cloningprimer.PlanSilentMutations(record, *frameStart, selected...)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while planning silent mutations: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("removing %v sites from %v (reading frame starts at position %d)\n", names, record.Name, *frameStart)
+	color.Unset() /* unset colorful output */
+	if len(plan.Mutations) == 0 {
+		fmt.Println("no site can be removed by a silent mutation")
+		printWarnings(plan.Warnings)
+		return
+	}
+	fmt.Println("----------------------------------------------------------------------\nSilent mutations:")
+	for _, m := range plan.Mutations {
+		fmt.Printf("%s at position %d (removes the %s site at position %d)\n", m, m.Position, m.Enzyme, m.Site)
+	}
+	printAssemblyPrimers(plan.Primers)
+	fmt.Println("----------------------------------------------------------------------\nFragments:")
+	for _, f := range plan.Fragments {
+		fmt.Printf("%s (%d nucleotides)\n", f.Name, len(f.Sequence))
+	}
+	printWarnings(plan.Warnings)
+	writeAssembly(plan.Primers, plan.Record(record.Name+"_silent"))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designSOE designs primers for an overlap extension PCR that fuses all records of the '--seq_file' (with the
+// '--linkers' between them) and prints them
+func designSOE(fragments []cloningprimer.FastaRecord) {
+	var opts cloningprimer.SOEOptions
+	if *linkers != "" {
+		opts.Linkers = strings.Split(strings.ReplaceAll(*linkers, " ", ""), ",")
+	}
+
+	// the overlap flags default to the values of a Gibson assembly, so they are only used if they are set explicitly
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "overlap_min":
+			opts.MinOverlap = *overlapMin
+		case "overlap_max":
+			opts.MaxOverlap = *overlapMax
+		case "overlap_tm":
+			opts.OverlapTm = *overlapTm
+		}
+	})
+	design, err := cloningprimer.DesignSOE(fragments, opts)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing overlap extension PCR: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	var names []string
+	for _, f := range design.Fragments {
+		names = append(names, f.Name)
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("fusing %v by overlap extension PCR (product of %d nucleotides)\n", strings.Join(names, ", "), len(design.Product))
+	color.Unset() /* unset colorful output */
+
+	// the outer primers have no tails, the tails of the inner primers are shown in lower case
+	printAssemblyPrimers(design.Primers)
+	printJunctions(design.Junctions)
+	fmt.Println("----------------------------------------------------------------------\nFirst PCR products:")
+	for _, a := range design.Amplicons {
+		fmt.Printf("%s (%d nucleotides)\n", a.Name, len(a.Sequence))
+	}
+	fmt.Println("----------------------------------------------------------------------\nFused product:")
+	color.Set(color.FgGreen, color.Bold) /* make output colorful */
+	fmt.Printf("result: %s\n", design.Product)
+	color.Unset() /* unset colorful ouput */
+	printWarnings(design.Warnings)
+	writeAssembly(design.Primers, design.Record(strings.Join(names, "_")))
+}
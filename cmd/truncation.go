@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	cloningprimer "github.com/DanielSchuette/cloningPrimer"
+	"github.com/fatih/color"
+)
+
+// designTruncations designs forward primers for the '--truncation_starts' and reverse primers for the
+// '--truncation_ends' of the selected coding sequence and prints the primers and the constructs of their pairs
+func designTruncations(records []cloningprimer.FastaRecord, enzymes map[string]cloningprimer.RestrictEnzyme) {
+	cds := selectRecord(records)
+	if (*truncStarts == "") || (*truncEnds == "") {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("invalid input: '--mode truncation' requires '--truncation_starts' and '--truncation_ends'\n")
+		color.Unset() /* unset colorful output */
+	}
+	enzymeF, enzymeR := lookupEnzyme(enzymes, *enzymeNameF), lookupEnzyme(enzymes, *enzymeNameR)
+	opts := cloningprimer.TruncationOptions{
+		Starts:    parseCodons("truncation_starts", *truncStarts),
+		Ends:      parseCodons("truncation_ends", *truncEnds),
+		Frame:     *frameStart,
+		RestrictF: enzymeF.RecognitionSite,
+		RestrictR: enzymeR.RecognitionSite,
+		LengthF:   *lengthF,
+		LengthR:   *lengthR,
+		RandomF:   *overhangF,
+		RandomR:   *overhangR,
+	}
+	series, err := cloningprimer.DesignTruncations(cds, opts)
+	if err != nil {
+		color.Set(color.FgRed) /* make output colorful */
+		log.Fatalf("error while designing truncation primers: %v\n", err)
+		color.Unset() /* unset colorful output */
+	}
+	color.Set(color.FgYellow) /* make output colorful */
+	fmt.Printf("designing %d truncation(s) of %v with %v (5') and %v (3')\n", len(series.Constructs), cds.Name, enzymeF.Name, enzymeR.Name)
+	color.Unset() /* unset colorful output */
+
+	// print every primer once, followed by the constructs of the primer pairs
+	color.Set(color.FgYellow, color.Bold) /* make output colorful */
+	fmt.Println("computing primers...")
+	color.Unset() /* unset colorful output */
+	var primers []cloningprimer.FastaRecord
+	for _, p := range append(series.Forward, series.Reverse...) {
+		fmt.Printf("%s (%d nucleotides, codon(s) %v)\n", p.Name, len(p.Sequence), p.Codons)
+		color.Set(color.FgGreen, color.Bold) /* make output colorful */
+		fmt.Printf("result: %s\n", p.Sequence)
+		color.Unset() /* unset colorful ouput */
+		primers = append(primers, cloningprimer.FastaRecord{Name: p.Name, Description: fmt.Sprintf("truncation primer of codon(s) %v", p.Codons), Sequence: p.Sequence})
+	}
+	fmt.Println("----------------------------------------------------------------------\nConstructs:")
+	for _, c := range series.Constructs {
+		fmt.Printf("%s: codons %d-%d (%d amino acids), %s + %s\n", c.Name, c.First, c.Last, len(strings.TrimSuffix(c.Protein, "*")), c.Forward, c.Reverse)
+	}
+	printWarnings(series.MethylationWarnings(cds.Sequence, enzymeF, enzymeR))
+
+	// write the primers to a FASTA file and the primer pair matrix to a CSV file if requested
+	if *fastaOut != "" {
+		if err := cloningprimer.WriteFastaToFile(*fastaOut, primers); err != nil {
+			log.Fatalf("error writing FASTA file: %v\n", err)
+		}
+		color.Set(color.FgGreen) /* make output colorful */
+		fmt.Printf("wrote primers to '%s'\n", *fastaOut)
+		color.Unset() /* unset colorful output */
+	}
+	if *matrixOut == "" {
+		fmt.Println("----------------------------------------------------------------------\nPrimer pair matrix:")
+		if err := series.WriteMatrix(os.Stdout); err != nil {
+			log.Fatalf("error writing primer pair matrix: %v\n", err)
+		}
+		return
+	}
+	f, err := os.Create(*matrixOut)
+	if err != nil {
+		log.Fatalf("error writing primer pair matrix: %v\n", err)
+	}
+	defer f.Close()
+	if err := series.WriteMatrix(f); err != nil {
+		log.Fatalf("error writing primer pair matrix: %v\n", err)
+	}
+	color.Set(color.FgGreen) /* make output colorful */
+	fmt.Printf("wrote primer pair matrix to '%s'\n", *matrixOut)
+	color.Unset() /* unset colorful output */
+}
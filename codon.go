@@ -0,0 +1,60 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// codonBases gives the order of the nucleotides in `codonTable'
+const codonBases = "TCAG"
+
+// codonTable lists the amino acids of the standard genetic code in the order TTT, TTC, TTA, TTG, TCT, ... GGG
+const codonTable = "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG"
+
+// GeneticCode maps the 64 codons of the standard genetic code to the one-letter codes of the amino acids they encode
+// ('*' for stop codons)
+var GeneticCode = newGeneticCode()
+
+// newGeneticCode returns the standard genetic code as a map from codons to amino acids
+func newGeneticCode() map[string]byte {
+	code := make(map[string]byte, len(codonTable))
+	for i := 0; i < len(codonTable); i++ {
+		codon := string([]byte{codonBases[i/16], codonBases[i/4%4], codonBases[i%4]})
+		code[codon] = codonTable[i]
+	}
+	return code
+}
+
+// Translate translates the complete codons of `seq' (starting with the first nucleotide) into a protein sequence of
+// one-letter amino acid codes ('*' for stop codons); nucleotides behind the last complete codon are ignored
+func Translate(seq string) (string, error) {
+	seq = strings.ToUpper(seq)
+	var protein []byte
+	for i := 0; i+Codon <= len(seq); i += Codon {
+		aa, ok := GeneticCode[seq[i:i+Codon]]
+		if !ok {
+			return "", fmt.Errorf("invalid input: codon %d (%s) is not a valid codon", i/Codon+1, seq[i:i+Codon])
+		}
+		protein = append(protein, aa)
+	}
+	return string(protein), nil
+}
+
+// SynonymousCodons returns the codons (in alphabetical order) that encode the same amino acid as `codon', excluding
+// `codon' itself
+func SynonymousCodons(codon string) []string {
+	codon = strings.ToUpper(codon)
+	aa, ok := GeneticCode[codon]
+	if !ok {
+		return nil
+	}
+	var synonyms []string
+	for c, a := range GeneticCode {
+		if (a == aa) && (c != codon) {
+			synonyms = append(synonyms, c)
+		}
+	}
+	sort.Strings(synonyms)
+	return synonyms
+}
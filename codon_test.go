@@ -0,0 +1,58 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "ATGGCTAAAGAGTAA", want: "MAKE*", err: nil},
+		// incomplete codons at the end are ignored and lower case nucleotides are accepted
+		{in: "atgtggtgc" + "GA", want: "MWC", err: nil},
+		{in: "ATGNNNTAA", want: "", err: errors.New("invalid input: codon 2 (NNN) is not a valid codon")},
+	}
+	for _, c := range cases {
+		got, err := Translate(c.in)
+		if got != c.want {
+			t.Errorf("Translate(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("Translate(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+
+	// the standard genetic code has 64 codons, 3 of which are stop codons
+	var stops int
+	for _, aa := range GeneticCode {
+		if aa == '*' {
+			stops++
+		}
+	}
+	if (len(GeneticCode) != 64) || (stops != 3) {
+		t.Errorf("GeneticCode has %d codons and %d stop codons, want 64 and 3\n", len(GeneticCode), stops)
+	}
+}
+
+func TestSynonymousCodons(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{in: "GGT", want: []string{"GGA", "GGC", "GGG"}},
+		{in: "agc", want: []string{"AGT", "TCA", "TCC", "TCG", "TCT"}},
+		{in: "ATG", want: nil},
+		{in: "TAA", want: []string{"TAG", "TGA"}},
+		{in: "NNN", want: nil},
+	}
+	for _, c := range cases {
+		if got := SynonymousCodons(c.in); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("SynonymousCodons(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+	}
+}
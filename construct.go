@@ -0,0 +1,91 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrimerPair holds a forward and a reverse primer together with the parameters that were passed to `FindForward' and
+// `FindReverse' to compute them
+type PrimerPair struct {
+	Forward string         /* forward primer (5' to 3') */
+	Reverse string         /* reverse primer (5' to 3') */
+	EnzymeF RestrictEnzyme /* restriction enzyme whose recognition site was added to the forward primer */
+	EnzymeR RestrictEnzyme /* restriction enzyme whose recognition site was added to the reverse primer */
+	StartF  int            /* `seqStart' of the forward primer (counted from the 5' end of the template) */
+	LengthF int            /* number of complementary nucleotides of the forward primer */
+	StartR  int            /* `seqStart' of the reverse primer (counted from the 3' end of the template) */
+	LengthR int            /* number of complementary nucleotides of the reverse primer */
+}
+
+// MethylationWarnings returns a warning for every site of `p.EnzymeF' and `p.EnzymeR' in the primers and in the template
+// `seq' that overlaps Dam or Dcm methylation which blocks or impairs the enzyme (see `CheckMethylation')
+func (p PrimerPair) MethylationWarnings(seq string) []string {
+	warnings := methylationWarnings("forward primer", p.Forward, p.EnzymeF)
+	warnings = append(warnings, methylationWarnings("reverse primer", p.Reverse, p.EnzymeR)...)
+	return append(warnings, methylationWarnings("template", seq, p.EnzymeF, p.EnzymeR)...)
+}
+
+// ConstructRecord returns a GenBank record named `name' of the PCR product that is amplified from the template `seq'
+// with the primers in `p' (see `PCRProduct'); the record is annotated with primer_bind features for both primers and
+// features for the restriction sites, the start and stop codons that were added by the primers and the amplified
+// part of the template
+func ConstructRecord(name, seq string, p PrimerPair) (GenBankRecord, error) {
+	seq = strings.ToUpper(seq)
+	product, err := PCRProduct(seq, p.Forward, p.Reverse, p.StartF, p.LengthF, p.StartR, p.LengthR)
+	if err != nil {
+		return GenBankRecord{}, err
+	}
+	forward, reverse := strings.ToUpper(p.Forward), strings.ToUpper(p.Reverse)
+	siteF, siteR := strings.ToUpper(p.EnzymeF.RecognitionSite), strings.ToUpper(p.EnzymeR.RecognitionSite)
+
+	// `FindForward' adds a start codon if the complementary part of the forward primer does not start with one and
+	// `FindReverse' adds a stop codon if the complementary part of the reverse primer does not start with one
+	codonF, codonR := 0, 0
+	if !HasStartCodon(seq[p.StartF-1:p.StartF-1+p.LengthF], true) {
+		codonF = 3
+	}
+	bindR := reverseComplementIUPAC(seq)[p.StartR-1 : p.StartR-1+p.LengthR]
+	if !HasStopCodon1(bindR, true) && !HasStopCodon2(bindR, true) && !HasStopCodon3(bindR, true) {
+		codonR = 3
+	}
+
+	// locate the recognition sites (1-based positions in `product')
+	endF := len(forward) - p.LengthF - codonF /* last nucleotide of the forward recognition site */
+	if (endF < len(siteF)) || (forward[endF-len(siteF):endF] != siteF) {
+		return GenBankRecord{}, fmt.Errorf("invalid input: forward primer %s does not contain the recognition site of %s (%s) in front of its complementary part", p.Forward, p.EnzymeF.Name, siteF)
+	}
+	startR := len(product) - len(reverse) + p.LengthR + codonR + 1 /* first nucleotide of the reverse recognition site */
+	if (len(reverse)-p.LengthR-codonR < len(siteR)) || (reverseComplementIUPAC(product[startR-1:startR-1+len(siteR)]) != siteR) {
+		return GenBankRecord{}, fmt.Errorf("invalid input: reverse primer %s does not contain the recognition site of %s (%s) in front of its complementary part", p.Reverse, p.EnzymeR.Name, siteR)
+	}
+
+	// annotate the construct
+	insertStart, insertEnd := len(forward)-p.LengthF+1, len(product)-len(reverse)+p.LengthR
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("PCR product of %s amplified with %s/%s primers", name, p.EnzymeF.Name, p.EnzymeR.Name),
+		Molecule:   "DNA",
+		Topology:   "linear",
+		Division:   "SYN",
+		Sequence:   product,
+	}
+	r.Features = append(r.Features,
+		syntheticSource(len(product)),
+		NewFeature("primer_bind", 1, len(forward), false, Qualifier{Key: "label", Value: name + "_F"}, Qualifier{Key: "note", Value: fmt.Sprintf("forward primer %s", forward)}),
+		NewFeature("misc_feature", endF-len(siteF)+1, endF, false, Qualifier{Key: "label", Value: p.EnzymeF.Name + " site"}),
+	)
+	if codonF > 0 {
+		r.Features = append(r.Features, NewFeature("misc_feature", endF+1, endF+3, false, Qualifier{Key: "label", Value: "start codon"}, Qualifier{Key: "note", Value: "added by the forward primer"}))
+	}
+	r.Features = append(r.Features, NewFeature("misc_feature", insertStart, insertEnd, false, Qualifier{Key: "label", Value: name},
+		Qualifier{Key: "note", Value: fmt.Sprintf("template nucleotides %d..%d", p.StartF, len(seq)-p.StartR+1)}))
+	if codonR > 0 {
+		r.Features = append(r.Features, NewFeature("misc_feature", insertEnd+1, insertEnd+3, false, Qualifier{Key: "label", Value: "stop codon"}, Qualifier{Key: "note", Value: "added by the reverse primer"}))
+	}
+	r.Features = append(r.Features,
+		NewFeature("misc_feature", startR, startR+len(siteR)-1, false, Qualifier{Key: "label", Value: p.EnzymeR.Name + " site"}),
+		NewFeature("primer_bind", len(product)-len(reverse)+1, len(product), true, Qualifier{Key: "label", Value: name + "_R"}, Qualifier{Key: "note", Value: fmt.Sprintf("reverse primer %s", reverse)}),
+	)
+	return r, nil
+}
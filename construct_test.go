@@ -0,0 +1,78 @@
+package cloningprimer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testCaseConstruct struct {
+	seq       string
+	length    int
+	enzymeF   RestrictEnzyme
+	locations []string
+	err       error
+}
+
+func TestConstructRecord(t *testing.T) {
+	ecoRI := RestrictEnzyme{Name: "EcoRI", RecognitionSite: "GAATTC"}
+	bamHI := RestrictEnzyme{Name: "BamHI", RecognitionSite: "GGATCC"}
+	cases := []testCaseConstruct{
+		// the template has a start and a stop codon
+		{
+			seq:       "ATGAAACCCGGGTTTAAAGGGCCCAAATAA",
+			length:    12,
+			enzymeF:   ecoRI,
+			locations: []string{"1..50", "1..22", "5..10", "11..40", "41..46", "complement(29..50)"},
+			err:       nil,
+		},
+		// the primers add a start and a stop codon
+		{
+			seq:       "CCCAAAGGGTTTCCCAAAGGGTTT",
+			length:    10,
+			enzymeF:   ecoRI,
+			locations: []string{"1..50", "1..23", "5..10", "11..13", "14..37", "38..40", "41..46", "complement(28..50)"},
+			err:       nil,
+		},
+		// the enzyme does not match the primer
+		{
+			seq:       "ATGAAACCCGGGTTTAAAGGGCCCAAATAA",
+			length:    12,
+			enzymeF:   RestrictEnzyme{Name: "HindIII", RecognitionSite: "AAGCTT"},
+			locations: nil,
+			err:       errors.New("invalid input: forward primer %s does not contain the recognition site of HindIII (AAGCTT) in front of its complementary part"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		forward, err := FindForward(c.seq, "GAATTC", 1, c.length, 4, true)
+		if err != nil {
+			t.Fatalf("FindForward(%v) == %v, want <nil>\n", c.seq, err)
+		}
+		reverse, err := FindReverse(c.seq, "GGATCC", 1, c.length, 4, true)
+		if err != nil {
+			t.Fatalf("FindReverse(%v) == %v, want <nil>\n", c.seq, err)
+		}
+		p := PrimerPair{Forward: forward, Reverse: reverse, EnzymeF: c.enzymeF, EnzymeR: bamHI, StartF: 1, LengthF: c.length, StartR: 1, LengthR: c.length}
+		r, err := ConstructRecord("test", c.seq, p)
+
+		// test similarity of expected and received feature locations
+		var got []string
+		for _, f := range r.Features {
+			got = append(got, f.Location)
+		}
+		if !reflect.DeepEqual(got, c.locations) {
+			t.Errorf("ConstructRecord(%v) feature locations == %v, want %v\n", c.seq, got, c.locations)
+		}
+
+		// test if the received error matches the expected error (the forward primer contains random nucleotides)
+		if c.err != nil {
+			c.err = fmt.Errorf(c.err.Error(), forward)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ConstructRecord(%v) == %v, want %v\n", c.seq, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package cloningprimer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// FormatEMBL identifies the EMBL flat file format (records start with an ID line)
+const FormatEMBL = "embl"
+
+// ParseEMBL parses all records from EMBL data in `r' (gzip-compressed data is decompressed transparently); the
+// records are returned as `GenBankRecord' structs because both formats hold the same information
+func ParseEMBL(r io.Reader) ([]GenBankRecord, error) {
+	b, err := readData(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseEMBL(b, "input")
+}
+
+// ParseEMBLFromFile parses all records from a (possibly gzip-compressed) EMBL file
+func ParseEMBLFromFile(file string) ([]GenBankRecord, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return parseEMBL(b, file)
+}
+
+// isEMBLHeader returns true if `text' is the ID line of an EMBL record, e.g.
+// "ID   X56734; SV 1; linear; mRNA; STD; PLN; 1859 BP." (in contrast to the ID lines of REBASE bairoch files)
+func isEMBLHeader(text string) bool {
+	return strings.HasPrefix(text, "ID   ") && strings.Contains(text, ";") && strings.HasSuffix(strings.ToUpper(text), "BP.")
+}
+
+// parseEMBL parses the contents `b' of an EMBL file; `source' identifies the data in errors
+func parseEMBL(b []byte, source string) ([]GenBankRecord, error) {
+	var records []GenBankRecord
+	var record *GenBankRecord  /* the record that is currently parsed */
+	var features featureParser /* parser of the feature table of the current record */
+	var inSequence bool        /* true if the SQ line of the current record was parsed */
+	var seq []byte             /* sequence of the current record */
+	var line int               /* variable to keep track of the current line (for error messages) */
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r ")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		// an ID line starts a new record, "//" ends it
+		if strings.HasPrefix(text, "ID ") {
+			record = parseEMBLHeader(text)
+			features, inSequence, seq = featureParser{}, false, nil
+			continue
+		}
+		if record == nil {
+			return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("expected an EMBL ID line")}
+		}
+		if strings.HasPrefix(text, "//") {
+			if err := features.finish(); err != nil {
+				return nil, &ParseError{Source: source, Line: line, Err: err}
+			}
+			record.Features, record.Sequence = features.features, strings.ToUpper(string(seq))
+			records = append(records, *record)
+			record = nil
+			continue
+		}
+
+		// every line starts with a two letter code, sequence lines start with spaces
+		if len(text) < 2 {
+			return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("expected a line code")}
+		}
+		var err error
+		value := ""
+		if len(text) > 5 {
+			value = strings.TrimSpace(text[5:])
+		}
+		switch code := text[:2]; {
+		case code == "  ":
+			if !inSequence {
+				return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("expected a line code")}
+			}
+			seq, err = appendSequenceLine(seq, text)
+		case code == "AC":
+			if record.Accession == "" {
+				record.Accession = strings.TrimSpace(strings.SplitN(value, ";", 2)[0])
+			}
+		case code == "DE":
+			record.Definition = strings.TrimSpace(record.Definition + " " + value)
+		case code == "DT":
+			if f := strings.Fields(value); (record.Date == "") && (len(f) > 0) && genBankDateRegexp.MatchString(f[0]) {
+				record.Date = f[0]
+			}
+		case code == "FT":
+			err = features.add("  " + text[2:])
+		case code == "SQ":
+			inSequence = true
+			err = features.finish()
+		}
+		if err != nil {
+			return nil, &ParseError{Source: source, Line: line, Err: err}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading EMBL data: %v", err)
+	}
+	if record != nil {
+		return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("record %s is not terminated by '//'", record.Name)}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("invalid input: no EMBL records found in %s", source)
+	}
+	logf("parsed %d EMBL record(s) from '%s'\n", len(records), source)
+	return records, nil
+}
+
+// parseEMBLHeader returns a record with the data of an EMBL ID line, e.g. "ID   X56734; SV 1; linear; mRNA; STD; PLN;
+// 1859 BP." or "ID   TRBG361    standard; mRNA; PLN; 1859 BP." (pre-2006 format)
+func parseEMBLHeader(text string) *GenBankRecord {
+	record := &GenBankRecord{Topology: "linear"}
+	var fields []string
+	for _, f := range strings.Split(strings.TrimSpace(text[2:]), ";") {
+		fields = append(fields, strings.TrimSpace(f))
+	}
+	if name := strings.Fields(fields[0]); len(name) > 0 {
+		record.Name = name[0]
+	}
+	if len(fields) >= 7 {
+		record.Topology, record.Molecule, record.Division = fields[2], fields[3], fields[5]
+		return record
+	}
+	for _, f := range fields[1:] {
+		switch {
+		case (f == "linear") || (f == "circular"):
+			record.Topology = f
+		case strings.Contains(f, "DNA") || strings.Contains(f, "RNA"):
+			record.Molecule = f
+		case (len(f) == 3) && (strings.ToUpper(f) == f):
+			record.Division = f
+		}
+	}
+	return record
+}
+
+// WriteEMBL writes `records' to `w' in EMBL flat file format; the files are parsed by `ParseEMBL'
+func WriteEMBL(w io.Writer, records []GenBankRecord) error {
+	var sb strings.Builder
+	for _, r := range records {
+		molecule, topology, division := r.Molecule, r.Topology, r.Division
+		if molecule == "" {
+			molecule = "DNA"
+		}
+		if topology == "" {
+			topology = "linear"
+		}
+		if division == "" {
+			division = "UNC" /* unclassified */
+		}
+		sb.WriteString(fmt.Sprintf("ID   %s; SV 1; %s; %s; STD; %s; %d BP.\nXX\n", r.Name, topology, molecule, division, len(r.Sequence)))
+		sb.WriteString(fmt.Sprintf("AC   %s;\nXX\n", r.Accession))
+		if r.Date != "" {
+			sb.WriteString(fmt.Sprintf("DT   %s\nXX\n", r.Date))
+		}
+		definition := r.Definition
+		if definition == "" {
+			definition = "."
+		}
+		writeWrapped(&sb, "DE   ", "DE   ", definition, true)
+		sb.WriteString("XX\n")
+		if len(r.Features) > 0 {
+			sb.WriteString("FH   Key             Location/Qualifiers\nFH\n")
+			writeFeatures(&sb, "FT   ", r.Features)
+			sb.WriteString("XX\n")
+		}
+
+		// the sequence is written in lines of 60 nucleotides in blocks of 10, followed by the position of the last one
+		counts := map[byte]int{}
+		seq := strings.ToLower(r.Sequence)
+		for i := 0; i < len(seq); i++ {
+			counts[seq[i]]++
+		}
+		sb.WriteString(fmt.Sprintf("SQ   Sequence %d BP; %d A; %d C; %d G; %d T; %d other;\n", len(seq), counts['a'], counts['c'], counts['g'], counts['t'],
+			len(seq)-counts['a']-counts['c']-counts['g']-counts['t']))
+		for i := 0; i < len(seq); i += 60 {
+			var blocks []string
+			end := i
+			for j := i; (j < i+60) && (j < len(seq)); j += 10 {
+				end = j + 10
+				if end > len(seq) {
+					end = len(seq)
+				}
+				blocks = append(blocks, seq[j:end])
+			}
+			sb.WriteString(fmt.Sprintf("     %-65s%10d\n", strings.Join(blocks, " "), end))
+		}
+		sb.WriteString("//\n")
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("error writing EMBL data: %v", err)
+	}
+	return nil
+}
+
+// WriteEMBLToFile writes `records' to `file' in EMBL flat file format (see `WriteEMBL')
+func WriteEMBLToFile(file string, records []GenBankRecord) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	err = WriteEMBL(f, records)
+	if closeErr := f.Close(); (err == nil) && (closeErr != nil) {
+		err = fmt.Errorf("error closing file: %v", closeErr)
+	}
+	return err
+}
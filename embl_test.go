@@ -0,0 +1,137 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCaseEMBL struct {
+	in   string
+	want *GenBankRecord
+	err  error
+}
+
+func TestParseEMBLFromFile(t *testing.T) {
+	records, err := ParseEMBLFromFile("tests/embl1.embl")
+	if err != nil {
+		t.Fatalf("ParseEMBLFromFile(tests/embl1.embl) == %v, want <nil>\n", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ParseEMBLFromFile(tests/embl1.embl) returned %d records, want 1\n", len(records))
+	}
+	r := records[0]
+
+	// test header data
+	got := []string{r.Name, r.Accession, r.Definition, r.Molecule, r.Topology, r.Division, r.Date}
+	want := []string{"pTEST1", "TEST0001", "Test plasmid with two coding sequences for cloningPrimer unit tests.", "DNA", "circular", "SYN", "19-OCT-2026"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEMBLFromFile(tests/embl1.embl) header == %q, want %q\n", got, want)
+	}
+
+	// the sequence and features equal those of the same record in GenBank format
+	gbRecords, err := ParseGenBankFromFile("tests/genbank1.gb")
+	if err != nil {
+		t.Fatalf("ParseGenBankFromFile(tests/genbank1.gb) == %v, want <nil>\n", err)
+	}
+	if r.Sequence != gbRecords[0].Sequence {
+		t.Errorf("ParseEMBLFromFile(tests/embl1.embl) sequence == %v, want %v\n", r.Sequence, gbRecords[0].Sequence)
+	}
+	if (len(r.Features) != 4) || !reflect.DeepEqual(r.Features[1], gbRecords[0].Features[2]) {
+		t.Errorf("ParseEMBLFromFile(tests/embl1.embl) features == %+v, want 4 features with %+v\n", r.Features, gbRecords[0].Features[2])
+	}
+	if f := r.Features[3]; (f.Location != "join(1..5,100..110)") || !f.Joined || (f.Qualifier("note") != "split \"feature\"") {
+		t.Errorf("ParseEMBLFromFile(tests/embl1.embl) feature 4 == %+v, want join(1..5,100..110) with a quoted note\n", f)
+	}
+	if f, err := r.FindCDS("geneB"); (err != nil) || !f.Complement {
+		t.Errorf("FindCDS(geneB) == %+v, %v, want complement(61..90), <nil>\n", f, err)
+	}
+}
+
+func TestParseEMBL(t *testing.T) {
+	cases := []testCaseEMBL{
+		// pre-2006 ID line
+		{
+			in:   "ID   TRBG361    standard; mRNA; PLN; 12 BP.\nSQ   Sequence 12 BP;\n     atggcc gcgtaa   12\n//\n",
+			want: &GenBankRecord{Name: "TRBG361", Molecule: "mRNA", Topology: "linear", Division: "PLN", Sequence: "ATGGCCGCGTAA"},
+			err:  nil,
+		},
+		// IUPAC codes are accepted, other letters are not
+		{
+			in:   "ID   x; SV 1; linear; DNA; STD; SYN; 4 BP.\nSQ   Sequence 4 BP;\n     atgn 4\n//\n",
+			want: &GenBankRecord{Name: "x", Molecule: "DNA", Topology: "linear", Division: "SYN", Sequence: "ATGN"},
+			err:  nil,
+		},
+		{
+			in:   "ID   x; SV 1; linear; DNA; STD; SYN; 4 BP.\nSQ   Sequence 4 BP;\n     atgx 4\n//\n",
+			want: nil,
+			err:  errors.New("input:3: invalid input x at position 4, expected sequence of lower or upper case A,T,C,G or IUPAC codes"),
+		},
+		// sequence line without SQ line
+		{
+			in:   "ID   x; SV 1; linear; DNA; STD; SYN; 4 BP.\n     atgc 4\n//\n",
+			want: nil,
+			err:  errors.New("input:2: expected a line code"),
+		},
+		// missing ID line
+		{
+			in:   "AC   x;\n",
+			want: nil,
+			err:  errors.New("input:1: expected an EMBL ID line"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		records, err := ParseEMBL(strings.NewReader(c.in))
+		var got *GenBankRecord
+		if len(records) > 0 {
+			got = &records[0]
+		}
+
+		// test similarity of expected and received value
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseEMBL(%q) == %+v, want %+v\n", c.in, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseEMBL(%q) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+
+	// records must be terminated
+	_, err := ParseEMBLFromFile("tests/embl2.embl")
+	wantErr := "tests/embl2.embl:6: record bad is not terminated by '//'"
+	if (err == nil) || (err.Error() != wantErr) {
+		t.Errorf("ParseEMBLFromFile(tests/embl2.embl) == %v, want %v\n", err, wantErr)
+	}
+}
+
+func TestWriteEMBL(t *testing.T) {
+	// written records are parsed again without loss of information, also after conversion to GenBank
+	records, err := ParseEMBLFromFile("tests/embl1.embl")
+	if err != nil {
+		t.Fatalf("ParseEMBLFromFile(tests/embl1.embl) == %v, want <nil>\n", err)
+	}
+	var sb strings.Builder
+	if err = WriteEMBL(&sb, records); err != nil {
+		t.Fatalf("WriteEMBL(tests/embl1.embl) == %v, want <nil>\n", err)
+	}
+	if !strings.Contains(sb.String(), "SQ   Sequence 124 BP; 31 A; 36 C; 31 G; 26 T; 0 other;\n") {
+		t.Errorf("WriteEMBL(tests/embl1.embl) == %v, want a SQ line with nucleotide counts\n", sb.String())
+	}
+	got, err := ParseFeatureRecords(strings.NewReader(sb.String()))
+	if !reflect.DeepEqual(got, records) || (err != nil) {
+		t.Errorf("ParseFeatureRecords(WriteEMBL(tests/embl1.embl)) == %+v, %v, want %+v, <nil>\n", got, err, records)
+	}
+	sb.Reset()
+	if err = WriteGenBank(&sb, records); err != nil {
+		t.Fatalf("WriteGenBank(tests/embl1.embl) == %v, want <nil>\n", err)
+	}
+	got, err = ParseFeatureRecords(strings.NewReader(sb.String()))
+	if !reflect.DeepEqual(got, records) || (err != nil) {
+		t.Errorf("ParseFeatureRecords(WriteGenBank(tests/embl1.embl)) == %+v, %v, want %+v, <nil>\n", got, err, records)
+	}
+}
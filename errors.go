@@ -0,0 +1,62 @@
+package cloningprimer
+
+import "fmt"
+
+// NucleotideError is returned if a sequence contains a letter that is not a valid nucleotide
+type NucleotideError struct {
+	Letter   rune /* the invalid letter */
+	Position int  /* position of the letter in the sequence (1-based, white space is not counted) */
+	IUPAC    bool /* true if IUPAC codes for ambiguous nucleotides (e.g. N) are accepted */
+}
+
+// Error implements the `error' interface
+func (e *NucleotideError) Error() string {
+	if e.IUPAC {
+		return fmt.Sprintf("invalid input %s at position %d, expected sequence of lower or upper case A,T,C,G or IUPAC codes", string(e.Letter), e.Position)
+	}
+	return fmt.Sprintf("invalid input %s at position %d, expected sequence of lower or upper case A,T,C,G", string(e.Letter), e.Position)
+}
+
+// ParseError is returned if a line of a parsed file (or other input) is malformed; use `errors.As' to inspect
+// the error and `errors.Unwrap' to get the underlying error (e.g. a `*NucleotideError')
+type ParseError struct {
+	Source string /* file path or a description of the parsed input */
+	Line   int    /* line number (1-based) of the malformed data */
+	Column int    /* column (1-based, counted in characters) of the malformed data or 0 if it is not known */
+	Err    error  /* the underlying error */
+}
+
+// Error implements the `error' interface
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d: %v", e.Source, e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.Source, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Logger is the interface of the optional logger that receives informational messages of this package
+// (e.g. the number of parsed enzymes); a `*log.Logger' satisfies this interface
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logger receives the informational messages of this package (messages are discarded if it is nil)
+var logger Logger
+
+// SetLogger sets the logger that receives informational messages of this package; by default (or if `l' is nil),
+// messages are discarded so that programs that use this package do not get unexpected output
+func SetLogger(l Logger) {
+	logger = l
+}
+
+// logf sends an informational message to the logger that was set with `SetLogger' (if any)
+func logf(format string, v ...interface{}) {
+	if logger != nil {
+		logger.Printf(format, v...)
+	}
+}
@@ -0,0 +1,61 @@
+package cloningprimer
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestParseErrorInspection(t *testing.T) {
+	_, err := ParseSequenceFromFile("tests/parse4.seq")
+
+	// the malformed line must be available from the `*ParseError'
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("ParseSequenceFromFile(tests/parse4.seq) == %T, want *ParseError\n", err)
+	}
+	if (parseErr.Source != "tests/parse4.seq") || (parseErr.Line != 7) {
+		t.Errorf("ParseSequenceFromFile(tests/parse4.seq) == %s:%d, want tests/parse4.seq:7\n", parseErr.Source, parseErr.Line)
+	}
+
+	// the invalid nucleotide must be available from the wrapped `*NucleotideError'
+	var nucleotideErr *NucleotideError
+	if !errors.As(err, &nucleotideErr) {
+		t.Fatalf("ParseSequenceFromFile(tests/parse4.seq) does not wrap a *NucleotideError\n")
+	}
+	if (nucleotideErr.Letter != 'Q') || (nucleotideErr.Position != 8) {
+		t.Errorf("ParseSequenceFromFile(tests/parse4.seq) == %c at %d, want Q at 8\n", nucleotideErr.Letter, nucleotideErr.Position)
+	}
+
+	// the lenient parser reports the column of invalid letters, too
+	_, err = ParseSequenceLenientFromFile("tests/parse4.seq")
+	if !errors.As(err, &parseErr) || (parseErr.Line != 7) || (parseErr.Column != 8) {
+		t.Errorf("ParseSequenceLenientFromFile(tests/parse4.seq) == %v, want an error at tests/parse4.seq:7:8\n", err)
+	}
+
+	// primer functions return a `*NucleotideError' for invalid input sequences
+	_, err = FindReverse("ATGCCGVDASTGASD", "GAATTC", 1, 10, 4, true)
+	if !errors.As(err, &nucleotideErr) || (nucleotideErr.Position != 7) {
+		t.Errorf("FindReverse() == %v, want a *NucleotideError at position 7\n", err)
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(log.New(&buf, "", 0))
+	defer SetLogger(nil)
+	if _, err := ParseSequenceFromFile("tests/parse1.seq"); err != nil {
+		t.Fatalf("ParseSequenceFromFile(tests/parse1.seq) == %v, want <nil>\n", err)
+	}
+	if got, want := buf.String(), "parsed 10 nucleotides from 'tests/parse1.seq'\n"; got != want {
+		t.Errorf("logged %q, want %q\n", got, want)
+	}
+
+	// nothing is logged without a logger
+	SetLogger(nil)
+	buf.Reset()
+	if _, err := ParseEnzymesFromFile("tests/parse1.re"); (err != nil) || (buf.Len() != 0) {
+		t.Errorf("ParseEnzymesFromFile(tests/parse1.re) logged %q (error: %v), want no output\n", buf.String(), err)
+	}
+}
@@ -0,0 +1,162 @@
+package cloningprimer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ExportRE identifies the *.re format (see ./app/assets/enzymes.re) for enzyme exports
+	ExportRE = "re"
+
+	// ExportJSON identifies the JSON format for enzyme exports
+	ExportJSON = "json"
+
+	// ExportCSV identifies the CSV format for enzyme exports
+	ExportCSV = "csv"
+)
+
+// ExportEnzymesToFile writes `enzymes' to `file'; `format' must be one of `ExportRE', `ExportJSON' or `ExportCSV'
+// or empty, in which case the format is derived from the extension of `file'
+func ExportEnzymesToFile(file, format string, enzymes map[string]RestrictEnzyme) error {
+	if format == "" {
+		format = strings.TrimPrefix(path.Ext(file), ".")
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	err = WriteEnzymes(f, format, enzymes)
+	if closeErr := f.Close(); (err == nil) && (closeErr != nil) {
+		err = fmt.Errorf("error closing file: %v", closeErr)
+	}
+	return err
+}
+
+// WriteEnzymes writes `enzymes' to `w' in the requested `format' (one of `ExportRE', `ExportJSON' or `ExportCSV')
+func WriteEnzymes(w io.Writer, format string, enzymes map[string]RestrictEnzyme) error {
+	switch format {
+	case ExportRE:
+		return WriteEnzymesRE(w, enzymes)
+	case ExportJSON:
+		return WriteEnzymesJSON(w, enzymes)
+	case ExportCSV:
+		return WriteEnzymesCSV(w, enzymes)
+	}
+	return fmt.Errorf("invalid input: unknown export format %q (must be one of %s, %s, %s)", format, ExportRE, ExportJSON, ExportCSV)
+}
+
+// reColumnWidths holds the widths of the columns in `reColumnsV2' that are used by `WriteEnzymesRE' (the last column is not padded)
+var reColumnWidths = []int{18, 38, 28, 12, 66, 10, 12, 12, 18, 14, 44, 18, 20, 12, 12, 12, 0}
+
+// WriteEnzymesRE writes `enzymes' to `w' in the latest version of the *.re format (see `REVersion'), sorted by
+// recognition site and name, so that the output can be parsed again with `ParseEnzymesFromFile'
+func WriteEnzymesRE(w io.Writer, enzymes map[string]RestrictEnzyme) error {
+	var sb strings.Builder
+	sb.WriteString("/* This file was generated by cloningPrimer (github.com/DanielSchuette/cloningPrimer).\n")
+	sb.WriteString(" * See ./app/assets/enzymes.re for a description of the columns.\n */\n")
+	sb.WriteString(fmt.Sprintf("re_version %d\n", REVersion))
+	writeRELine(&sb, reColumnsV2)
+	for _, e := range sortedEnzymes(enzymes) {
+		items := reItems(e)
+		for i := range items {
+			items[i] = quoteRE(items[i])
+		}
+		writeRELine(&sb, items)
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("error writing *.re data: %v", err)
+	}
+	return nil
+}
+
+// writeRELine writes a single line of padded *.re columns to `sb'
+func writeRELine(sb *strings.Builder, items []string) {
+	for i, item := range items {
+		if i == len(items)-1 {
+			sb.WriteString(item + "\n")
+			break
+		}
+		sb.WriteString(fmt.Sprintf("%-*s", reColumnWidths[i], item))
+		if len(item) >= reColumnWidths[i] {
+			sb.WriteString(" ")
+		}
+	}
+}
+
+// reItems returns the data items of `e' in the order of `reColumnsV2'
+func reItems(e RestrictEnzyme) []string {
+	var star string
+	if e.StarActivity {
+		star = "yes"
+	}
+	var top, bottom string
+	if e.CutKnown {
+		top, bottom = strconv.Itoa(e.TopCut), strconv.Itoa(e.BottomCut)
+	}
+	return []string{
+		e.Name, e.RecognitionSite, e.NoPalinCleav, e.ID, strings.Join(e.Isoschizomeres, ","), top, bottom,
+		strings.Join(e.Suppliers, ""), e.MethylationSite, e.Buffer, FormatBufferActivity(e.BufferActivity), formatTemperature(e.IncubationTemp), formatTemperature(e.InactivationTemp),
+		string(e.Dam), string(e.Dcm), string(e.CpG), star,
+	}
+}
+
+// formatTemperature formats a temperature for *.re and CSV exports (unknown temperatures are empty)
+func formatTemperature(t int) string {
+	if t == 0 {
+		return ""
+	}
+	return strconv.Itoa(t)
+}
+
+// WriteEnzymesJSON writes `enzymes' to `w' as an indented JSON array, sorted by recognition site and name
+func WriteEnzymesJSON(w io.Writer, enzymes map[string]RestrictEnzyme) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sortedEnzymes(enzymes)); err != nil {
+		return fmt.Errorf("error writing JSON data: %v", err)
+	}
+	return nil
+}
+
+// WriteEnzymesCSV writes `enzymes' to `w' as a CSV table with a header row, sorted by recognition site and name;
+// list fields (isoschizomers and suppliers) are comma separated within their cell and unknown cut positions are empty
+func WriteEnzymesCSV(w io.Writer, enzymes map[string]RestrictEnzyme) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"name", "recognition_site", "non_palindromic_cleavage", "pdb_id", "isoschizomers", "top_cut", "bottom_cut", "suppliers", "methylation_site",
+		"buffer", "buffer_activity", "incubation_temp", "inactivation_temp", "dam", "dcm", "cpg", "star_activity"}}
+	for _, e := range sortedEnzymes(enzymes) {
+		records = append(records, reItems(e))
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("error writing CSV data: %v", err)
+	}
+	return nil
+}
+
+// sortedEnzymes returns the values of `enzymes' sorted by recognition site and name (like ./app/assets/enzymes.re)
+func sortedEnzymes(enzymes map[string]RestrictEnzyme) []RestrictEnzyme {
+	list := make([]RestrictEnzyme, 0, len(enzymes))
+	for _, e := range enzymes {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].RecognitionSite != list[j].RecognitionSite {
+			return list[i].RecognitionSite < list[j].RecognitionSite
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// quoteRE wraps a data item in the single quotes that delimit columns in *.re files
+func quoteRE(item string) string {
+	return "'" + item + "'"
+}
@@ -0,0 +1,139 @@
+package cloningprimer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type testCaseExport struct {
+	in   exportInput
+	want string
+	err  error
+}
+
+type exportInput struct {
+	format  string
+	enzymes map[string]RestrictEnzyme
+}
+
+func TestWriteEnzymes(t *testing.T) {
+	enzymes := map[string]RestrictEnzyme{
+		"EcoRI": {Name: "EcoRI", RecognitionSite: "GAATTC", NoPalinCleav: "no", Isoschizomeres: []string{"AaaI", "AbfI"}, TopCut: 1, BottomCut: 5, CutKnown: true, Suppliers: []string{"B", "N"}, MethylationSite: "3(6)",
+			Buffer: "rCutSmart", BufferActivity: map[string]int{"r3.1": 50, "rCutSmart": 100}, IncubationTemp: 37, InactivationTemp: 65, Dam: MethylationNotSensitive, Dcm: MethylationNotSensitive, CpG: MethylationImpaired, StarActivity: true},
+		"AclI": {Name: "AclI", RecognitionSite: "AACGTT", NoPalinCleav: "no"},
+	}
+	cases := []testCaseExport{
+		// test *.re output (latest version, sorted by recognition site, unknown cut positions are left empty)
+		{
+			in: exportInput{ExportRE, enzymes},
+			want: "/* This file was generated by cloningPrimer (github.com/DanielSchuette/cloningPrimer).\n" +
+				" * See ./app/assets/enzymes.re for a description of the columns.\n */\n" +
+				"re_version 2\n" +
+				"enzyme_name       recognition_sequence                  non_palindromic_cleavage    PDB_ID      isoschizomers                                                     top_cut   bottom_cut  suppliers   methylation_site  buffer        buffer_activity                             incubation_temp   inactivation_temp   dam         dcm         cpg         star_activity\n" +
+				"'AclI'            'AACGTT'                              'no'                        ''          ''                                                                ''        ''          ''          ''                ''            ''                                          ''                ''                  ''          ''          ''          ''\n" +
+				"'EcoRI'           'GAATTC'                              'no'                        ''          'AaaI,AbfI'                                                       '1'       '5'         'BN'        '3(6)'            'rCutSmart'   'r3.1:50,rCutSmart:100'                     '37'              '65'                'no'        'no'        'impaired'  'yes'\n",
+			err: nil,
+		},
+		// test CSV output, unknown cut positions and temperatures are left empty
+		{
+			in: exportInput{ExportCSV, enzymes},
+			want: "name,recognition_site,non_palindromic_cleavage,pdb_id,isoschizomers,top_cut,bottom_cut,suppliers,methylation_site,buffer,buffer_activity,incubation_temp,inactivation_temp,dam,dcm,cpg,star_activity\n" +
+				"AclI,AACGTT,no,,,,,,,,,,,,,,\n" +
+				"EcoRI,GAATTC,no,,\"AaaI,AbfI\",1,5,BN,3(6),rCutSmart,\"r3.1:50,rCutSmart:100\",37,65,no,no,impaired,yes\n",
+			err: nil,
+		},
+		// test unknown format
+		{
+			in:   exportInput{"xml", enzymes},
+			want: "",
+			err:  errors.New(`invalid input: unknown export format "xml" (must be one of re, json, csv)`),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := WriteEnzymes(&buf, c.in.format, c.in.enzymes)
+
+		// test similarity of expected and received value
+		if got := buf.String(); got != c.want {
+			t.Errorf("WriteEnzymes(%v) == %q, want %q\n", c.in.format, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("WriteEnzymes(%v) == %v, want %v\n", c.in.format, err, c.err)
+		}
+	}
+}
+
+func TestWriteEnzymesJSON(t *testing.T) {
+	// JSON output must decode into the same enzymes
+	want := map[string]RestrictEnzyme{
+		"BsaI": {Name: "BsaI", RecognitionSite: "GGTCTC", NoPalinCleav: "()(1/5)", TopCut: 7, BottomCut: 11, CutKnown: true, Suppliers: []string{"N"}, Dcm: MethylationImpaired},
+	}
+	var buf bytes.Buffer
+	if err := WriteEnzymesJSON(&buf, want); err != nil {
+		t.Fatalf("WriteEnzymesJSON(%v) == %v, want nil\n", want, err)
+	}
+	var list []RestrictEnzyme
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatalf("json.Unmarshal(%s) == %v, want nil\n", buf.String(), err)
+	}
+	got := make(map[string]RestrictEnzyme)
+	for _, e := range list {
+		got[e.Name] = e
+	}
+	if !isSimilarMap(got, want) {
+		t.Errorf("WriteEnzymesJSON(%v) == %v\n", want, buf.String())
+	}
+}
+
+func TestWriteEnzymesRERoundTrip(t *testing.T) {
+	// *.re output must parse into the same enzymes, with every field set
+	want := map[string]RestrictEnzyme{
+		"EcoRI": {Name: "EcoRI", RecognitionSite: "GAATTC", NoPalinCleav: "no", ID: "1ERI", Isoschizomeres: []string{"AaaI", "AbfI"}, TopCut: 1, BottomCut: 5, CutKnown: true,
+			Suppliers: []string{"B", "N"}, MethylationSite: "3(6)", Buffer: "rCutSmart", BufferActivity: map[string]int{"r3.1": 50, "rCutSmart": 100}, IncubationTemp: 37,
+			InactivationTemp: 65, Dam: MethylationNotSensitive, Dcm: MethylationBlocked, CpG: MethylationImpaired, StarActivity: true},
+	}
+	var buf bytes.Buffer
+	if err := WriteEnzymesRE(&buf, want); err != nil {
+		t.Fatalf("WriteEnzymesRE(%v) == %v, want nil\n", want, err)
+	}
+	got, err := ParseEnzymes(&buf)
+	if err != nil {
+		t.Fatalf("ParseEnzymes(WriteEnzymesRE(%v)) == %v, want nil\n", want, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEnzymes(WriteEnzymesRE(%v)) == %v\n", want, got)
+	}
+}
+
+func TestExportEnzymesToFileRoundTrip(t *testing.T) {
+	// parse an enzyme file, export it to a temporary *.re file and parse it again
+	want, err := ParseEnzymesFromFile("tests/parse3.re")
+	if err != nil {
+		t.Fatalf("ParseEnzymesFromFile(tests/parse3.re) == %v, want nil\n", err)
+	}
+	dir, err := os.MkdirTemp("", "cloningprimer")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "export.re")
+	if err := ExportEnzymesToFile(file, "", want); err != nil {
+		t.Fatalf("ExportEnzymesToFile(%v) == %v, want nil\n", file, err)
+	}
+	got, err := ParseEnzymesFromFile(file)
+	if err != nil {
+		t.Fatalf("ParseEnzymesFromFile(%v) == %v, want nil\n", file, err)
+	}
+	if !isSimilarMap(got, want) {
+		t.Errorf("ParseEnzymesFromFile(ExportEnzymesToFile(%v)) == %v, want %v\n", file, got, want)
+	}
+}
@@ -0,0 +1,187 @@
+package cloningprimer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	// FormatFasta identifies the FASTA sequence format (records start with a '>' header line)
+	FormatFasta = "fasta"
+
+	// FastaLineWidth is the number of nucleotides per line in FASTA files that are written by `WriteFasta'
+	FastaLineWidth = 60
+)
+
+// sequenceExtensions holds the file extensions (besides *.seq) that are accepted by the path-based sequence parsers
+var sequenceExtensions = map[string]bool{".fa": true, ".fasta": true, ".fna": true, ".fas": true, ".ffn": true, ".gb": true, ".gbk": true, ".genbank": true, ".embl": true, ".dna": true}
+
+// FastaRecord holds a single record of a FASTA file (or another sequence file)
+type FastaRecord struct {
+	Name        string /* first word of the header line, e.g. NM_000546.6 */
+	Description string /* rest of the header line */
+	Sequence    string /* nucleotide sequence (the case of the letters is preserved) */
+}
+
+// ParseFasta parses all records from FASTA data in `r' (gzip-compressed data is decompressed transparently); every
+// record must only contain valid nucleotides or IUPAC codes (see `IsIUPACNucleotide'), white space within sequence lines
+// is ignored; `FindForward' and `FindReverse' only accept unknown nucleotides (N) outside of the region a primer binds to
+func ParseFasta(r io.Reader) ([]FastaRecord, error) {
+	b, err := readData(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseFasta(b, "input")
+}
+
+// ParseFastaFromFile parses all records from a (possibly gzip-compressed) FASTA file
+func ParseFastaFromFile(file string) ([]FastaRecord, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return parseFasta(b, file)
+}
+
+// ParseSequenceRecords parses the records from sequence data in `r' in any of the supported sequence formats
+// (`FormatFasta', `FormatGenBank', `FormatEMBL', `FormatSnapGene' or `FormatSeq'); *.seq and SnapGene data is
+// returned as a single record without a name
+func ParseSequenceRecords(r io.Reader) ([]FastaRecord, error) {
+	b, err := readData(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseSequenceRecords(b, "input", "")
+}
+
+// ParseSequenceRecordsFromFile parses the records from a (possibly gzip-compressed) sequence file in any of the supported
+// sequence formats; the record of a *.seq or SnapGene file is named after the file (e.g. "tp53" for ./app/assets/tp53.seq)
+func ParseSequenceRecordsFromFile(file string) ([]FastaRecord, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	base := path.Base(strings.TrimSuffix(file, ".gz"))
+	return parseSequenceRecords(b, file, strings.TrimSuffix(base, path.Ext(base)))
+}
+
+// parseSequenceRecords parses FASTA, GenBank, EMBL, SnapGene or *.seq data `b'; `source' identifies the data in errors and
+// `name' is the name of the single record in *.seq and SnapGene data
+func parseSequenceRecords(b []byte, source, name string) ([]FastaRecord, error) {
+	switch DetectFormat(b) {
+	case FormatFasta:
+		return parseFasta(b, source)
+	case FormatGenBank, FormatEMBL:
+		gbRecords, err := parseFeatureRecords(b, source, name)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]FastaRecord, 0, len(gbRecords))
+		for _, r := range gbRecords {
+			records = append(records, FastaRecord{Name: r.Name, Description: r.Definition, Sequence: r.Sequence})
+		}
+		return records, nil
+	case FormatSnapGene:
+		r, err := parseSnapGene(b, source)
+		if err != nil {
+			return nil, err
+		}
+		return []FastaRecord{{Name: name, Description: r.Definition, Sequence: r.Sequence}}, nil
+	}
+	seq, err := parseSequence(b, source) /* like `ParseSequence', the sequence up to an invalid letter is returned */
+	return []FastaRecord{{Name: name, Sequence: seq}}, err
+}
+
+// parseFasta parses the contents `b' of a FASTA file; `source' identifies the data in errors
+func parseFasta(b []byte, source string) ([]FastaRecord, error) {
+	var records []FastaRecord
+	var seq []byte /* sequence of the current record */
+	var line int   /* variable to keep track of the current line (for error messages) */
+	addRecord := func() {
+		if len(records) > 0 {
+			records[len(records)-1].Sequence = string(seq)
+		}
+		seq = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case (text == "") || strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, ">"):
+			addRecord()
+			header := strings.TrimSpace(text[1:])
+			record := FastaRecord{Name: header}
+			if i := strings.IndexAny(header, " \t"); i >= 0 { /* the name ends at the first space or tab */
+				record.Name, record.Description = header[:i], strings.TrimSpace(header[i:])
+			}
+			records = append(records, record)
+			continue
+		case len(records) == 0:
+			return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("expected a FASTA header line starting with '>'")}
+		}
+		for i := 0; i < len(text); i++ {
+			switch {
+			case (text[i] == ' ') || (text[i] == '\t'):
+				continue
+			case !IsIUPACNucleotide(text[i]):
+				return nil, &ParseError{Source: source, Line: line, Err: &NucleotideError{Letter: rune(text[i]), Position: len(seq) + 1, IUPAC: true}}
+			}
+			seq = append(seq, text[i])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading FASTA data: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("invalid input: no FASTA records found in %s", source)
+	}
+	addRecord()
+	logf("parsed %d FASTA record(s) from '%s'\n", len(records), source)
+	return records, nil
+}
+
+// WriteFasta writes `records' to `w' in FASTA format with `FastaLineWidth' nucleotides per line
+func WriteFasta(w io.Writer, records []FastaRecord) error {
+	var sb strings.Builder
+	for _, r := range records {
+		sb.WriteString(">" + r.Name)
+		if r.Description != "" {
+			sb.WriteString(" " + r.Description)
+		}
+		sb.WriteString("\n")
+		for i := 0; i < len(r.Sequence); i += FastaLineWidth {
+			end := i + FastaLineWidth
+			if end > len(r.Sequence) {
+				end = len(r.Sequence)
+			}
+			sb.WriteString(r.Sequence[i:end] + "\n")
+		}
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("error writing FASTA data: %v", err)
+	}
+	return nil
+}
+
+// WriteFastaToFile writes `records' to `file' in FASTA format (see `WriteFasta')
+func WriteFastaToFile(file string, records []FastaRecord) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	err = WriteFasta(f, records)
+	if closeErr := f.Close(); (err == nil) && (closeErr != nil) {
+		err = fmt.Errorf("error closing file: %v", closeErr)
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package cloningprimer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCaseFasta struct {
+	in   string
+	want []FastaRecord
+	err  error
+}
+
+func TestParseFastaFromFile(t *testing.T) {
+	cases := []testCaseFasta{
+		// multiple records with descriptions, line breaks, empty lines, comments and lower case letters
+		{
+			in: "tests/fasta1.fa",
+			want: []FastaRecord{
+				{Name: "NM_000001.1", Description: "test gene 1, mRNA", Sequence: "ATGGCCGCGTTGACGAGTGAGCATAG"},
+				{Name: "seq2", Sequence: "atggccgcgtTAA"},
+			},
+			err: nil,
+		},
+		// tab-separated descriptions
+		{
+			in: "tests/fasta3.fa",
+			want: []FastaRecord{
+				{Name: "ENST00000269305.9", Description: "cds:protein_coding\tgene:ENSG00000141510.18", Sequence: "ATGGAGGAGCCGCAGTCAGATCCTAG"},
+			},
+			err: nil,
+		},
+		// invalid nucleotide (the N in front of it is a valid IUPAC code)
+		{
+			in:   "tests/fasta2.fa",
+			want: nil,
+			err:  errors.New("tests/fasta2.fa:3: invalid input X at position 9, expected sequence of lower or upper case A,T,C,G or IUPAC codes"),
+		},
+		// sequence without header
+		{
+			in:   "tests/parse1.seq",
+			want: nil,
+			err:  errors.New("tests/parse1.seq:1: expected a FASTA header line starting with '>'"),
+		},
+		{
+			in:   "tests/doesnotexist.fa",
+			want: nil,
+			err:  errors.New("error opening file: open tests/doesnotexist.fa: no such file or directory"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseFastaFromFile(c.in)
+
+		// test similarity of expected and received value
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseFastaFromFile(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseFastaFromFile(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
+
+func TestParseSequenceRecordsFromFile(t *testing.T) {
+	cases := []testCaseFasta{
+		{
+			in:   "tests/parse2.seq",
+			want: []FastaRecord{{Name: "parse2", Sequence: "ATGGCCGCGT"}},
+			err:  nil,
+		},
+		{
+			in:   "tests/fasta1.fa",
+			want: []FastaRecord{{Name: "NM_000001.1", Description: "test gene 1, mRNA", Sequence: "ATGGCCGCGTTGACGAGTGAGCATAG"}, {Name: "seq2", Sequence: "atggccgcgtTAA"}},
+			err:  nil,
+		},
+		{
+			in:   "tests/genbank2.gb",
+			want: nil,
+			err:  errors.New("tests/genbank2.gb:3: invalid input x at position 4, expected sequence of lower or upper case A,T,C,G or IUPAC codes"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseSequenceRecordsFromFile(c.in)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseSequenceRecordsFromFile(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseSequenceRecordsFromFile(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+
+	// FASTA files are accepted wherever *.seq files are accepted (the first record is used)
+	seq, err := ParseSequenceFromFile("tests/fasta1.fa")
+	if (seq != "ATGGCCGCGTTGACGAGTGAGCATAG") || (err != nil) {
+		t.Errorf("ParseSequenceFromFile(tests/fasta1.fa) == %v, %v, want ATGGCCGCGTTGACGAGTGAGCATAG, <nil>\n", seq, err)
+	}
+}
+
+func TestWriteFasta(t *testing.T) {
+	records := []FastaRecord{
+		{Name: "long", Description: "a sequence with more than one line", Sequence: strings.Repeat("ACGT", 20)},
+		{Name: "short", Sequence: "ATG"},
+	}
+	want := ">long a sequence with more than one line\n" + strings.Repeat("ACGT", 15) + "\n" + strings.Repeat("ACGT", 5) + "\n>short\nATG\n"
+
+	var sb strings.Builder
+	if err := WriteFasta(&sb, records); err != nil {
+		t.Fatalf("WriteFasta() == %v, want <nil>\n", err)
+	}
+	if sb.String() != want {
+		t.Errorf("WriteFasta() == %q, want %q\n", sb.String(), want)
+	}
+
+	// written files can be parsed again
+	dir, err := os.MkdirTemp("", "cloningprimer")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "records.fa")
+	if err := WriteFastaToFile(file, records); err != nil {
+		t.Fatalf("WriteFastaToFile() == %v, want <nil>\n", err)
+	}
+	got, err := ParseFastaFromFile(file)
+	if (err != nil) || !reflect.DeepEqual(got, records) {
+		t.Errorf("ParseFastaFromFile(WriteFastaToFile()) == %v, %v, want %v, <nil>\n", got, err, records)
+	}
+}
@@ -0,0 +1,193 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// AttB1 is the attB1 site that Gateway forward primers add in front of the ORF
+	AttB1 = "ACAAGTTTGTACAAAAAAGCAGGCT"
+
+	// AttB2 is the attB2 site that Gateway reverse primers add behind the ORF (5' to 3' as in the reverse primer)
+	AttB2 = "ACCACTTTGTACAAGAAAGCTGGGT"
+
+	// GatewayPadding is added to the 5' end of Gateway primers for efficient BP recombination
+	GatewayPadding = "GGGG"
+
+	// KozakSequence is added in front of the start codon for expression in eukaryotic cells
+	KozakSequence = "GCCACC"
+
+	// ShineDalgarnoSequence is a ribosome binding site (with spacer) that is added in front of the start codon for
+	// expression in E. coli
+	ShineDalgarnoSequence = "AAGGAGATATACAT"
+
+	// attCore1 and attCore2 are the core regions (top strand of the attB PCR product) in which attB1/attP1 and
+	// attB2/attP2 recombine; they are identical in the attB, attP, attL and attR sites
+	attCore1 = "TTTGTACAAAAAAGC"
+	attCore2 = "CTTTCTTGTACAAAG"
+
+	// attB1Frame and attB2Frame keep an ORF in frame with the reading frame of attB1 (N-terminal fusions) and attB2
+	// (C-terminal fusions); they complete the last codon of attB1 (TCC) and the first codon of attB2 (CAC)
+	attB1Frame = "CC"
+	attB2Frame = "G"
+)
+
+// GatewayOptions holds the parameters of `DesignGateway'
+type GatewayOptions struct {
+	NTerminalFusion bool    /* keep the ORF in frame with attB1 for N-terminal tags of the destination vector */
+	CTerminalFusion bool    /* remove the stop codon and keep the ORF in frame with attB2 for C-terminal tags */
+	Kozak           bool    /* add `KozakSequence' in front of the start codon */
+	ShineDalgarno   bool    /* add `ShineDalgarnoSequence' in front of the start codon */
+	AnnealTm        float64 /* Tm of the annealing parts of the primers in °C (defaults to `AssemblyAnnealTm') */
+}
+
+// GatewayDesign holds the primers and the attB PCR product of an ORF for Gateway cloning
+type GatewayDesign struct {
+	ORF      FastaRecord      /* the ORF as it is amplified (with added start or stop codon, without removed stop codon) */
+	Primers  []AssemblyPrimer /* the attB1 forward and the attB2 reverse primer */
+	Product  string           /* sequence of the attB PCR product */
+	Warnings []string         /* in-frame stop codons and primers that do not meet all requirements */
+	orfStart int              /* 1-based position of the ORF in `Product' */
+}
+
+// Record returns the attB PCR product as a linear GenBank record named `name' with features for the attB sites and
+// the ORF
+func (d GatewayDesign) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("attB PCR product of %s", d.ORF.Name),
+		Molecule:   "DNA",
+		Topology:   "linear",
+		Division:   "SYN",
+		Sequence:   d.Product,
+	}
+	r.Features = append(r.Features,
+		syntheticSource(len(d.Product)),
+		NewFeature("protein_bind", len(GatewayPadding)+1, len(GatewayPadding)+len(AttB1), false, Qualifier{Key: "label", Value: "attB1"}),
+		NewFeature("CDS", d.orfStart, d.orfStart+len(d.ORF.Sequence)-1, false, Qualifier{Key: "label", Value: d.ORF.Name}),
+		NewFeature("protein_bind", len(d.Product)-len(GatewayPadding)-len(AttB2)+1, len(d.Product)-len(GatewayPadding), true, Qualifier{Key: "label", Value: "attB2"}),
+	)
+	return r
+}
+
+// DesignGateway designs an attB1 forward and an attB2 reverse primer that amplify the ORF `orf' for Gateway cloning;
+// a start codon is added if the ORF does not start with one (unless `opts.NTerminalFusion' is set) and a stop codon
+// (TAA) is added if the ORF does not end with one (unless `opts.CTerminalFusion' is set, which removes an existing
+// stop codon instead); for fusions, the ORF is kept in frame with attB1 and attB2, respectively
+func DesignGateway(orf FastaRecord, opts GatewayOptions) (GatewayDesign, error) {
+	seq, err := ValidateSequence([]byte(orf.Sequence))
+	if err != nil {
+		return GatewayDesign{}, fmt.Errorf("invalid sequence: %v", err)
+	}
+	switch {
+	case len(seq)%Codon != 0:
+		return GatewayDesign{}, fmt.Errorf("invalid input: the ORF has %d nucleotides, which is not a multiple of %d", len(seq), Codon)
+	case opts.Kozak && opts.ShineDalgarno:
+		return GatewayDesign{}, fmt.Errorf("invalid input: add either a Kozak or a Shine-Dalgarno sequence, not both")
+	case opts.NTerminalFusion && (opts.Kozak || opts.ShineDalgarno):
+		return GatewayDesign{}, fmt.Errorf("invalid input: a Kozak or Shine-Dalgarno sequence cannot be used with an N-terminal fusion")
+	}
+	if opts.AnnealTm == 0 {
+		opts.AnnealTm = AssemblyAnnealTm
+	}
+	if orf.Name == "" {
+		orf.Name = "orf"
+	}
+
+	// add or remove start and stop codons
+	d := GatewayDesign{ORF: orf}
+	var startCodon, stopCodon string
+	if !opts.NTerminalFusion && !HasStartCodon(seq, true) {
+		startCodon = "ATG"
+	}
+	protein, err := Translate(seq)
+	if err != nil {
+		return GatewayDesign{}, err
+	}
+	hasStop := strings.HasSuffix(protein, "*")
+	switch {
+	case opts.CTerminalFusion && hasStop:
+		seq, protein = seq[:len(seq)-Codon], protein[:len(protein)-1]
+	case !opts.CTerminalFusion && !hasStop:
+		stopCodon = "TAA"
+	}
+	if i := strings.Index(strings.TrimSuffix(protein, "*"), "*"); i >= 0 {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("the ORF contains an in-frame stop codon at codon %d", i+1))
+	}
+	d.ORF.Sequence = startCodon + seq + stopCodon
+
+	// the primers consist of padding, attB site, frame nucleotides or translation initiation site, added codons and
+	// the annealing part
+	tailF := GatewayPadding + AttB1
+	switch {
+	case opts.NTerminalFusion:
+		tailF += attB1Frame
+	case opts.Kozak:
+		tailF += KozakSequence
+	case opts.ShineDalgarno:
+		tailF += ShineDalgarnoSequence
+	}
+	tailF += startCodon
+	tailR := GatewayPadding + AttB2
+	if opts.CTerminalFusion {
+		tailR += attB2Frame
+	}
+	tailR += reverseComplementIUPAC(stopCodon)
+	primers, warnings := tailedPrimers(opts.AnnealTm, tailedPrimer{orf.Name + "_attB1_F", seq, tailF}, tailedPrimer{orf.Name + "_attB2_R", reverseComplementIUPAC(seq), tailR})
+	d.Primers = append(d.Primers, primers...)
+	d.Warnings = append(d.Warnings, warnings...)
+	d.Product = tailF + seq + reverseComplementIUPAC(tailR)
+	d.orfStart = len(tailF) - len(startCodon) + 1
+	return d, nil
+}
+
+// BPRecombination returns the entry clone of a BP reaction of the attB PCR `product' with the `donor' vector (whose
+// attP1 and attP2 sites are oriented like in pDONR vectors); the attB sites and the attP sites recombine in their
+// identical core regions, such that the part of the donor between attP1 and attP2 (e.g. the ccdB cassette) is
+// replaced by the part of the product between attB1 and attB2; the circular entry clone starts with the core of attL1
+func BPRecombination(product, donor string) (string, error) {
+	product, donor = strings.ToUpper(product), strings.ToUpper(donor)
+	j1, j2 := strings.Index(product, attCore1), strings.Index(product, attCore2)
+	if (j1 < 0) || (j2 < j1) || (strings.Count(product, attCore1) != 1) || (strings.Count(product, attCore2) != 1) {
+		return "", fmt.Errorf("invalid input: the product must contain attB1 and attB2 exactly once (in this order)")
+	}
+
+	// the donor is circular, so attP2 may be found in front of attP1
+	circular := donor + donor[:len(attCore2)-1]
+	i1, i2 := strings.Index(circular, attCore1), strings.Index(circular, attCore2)
+	if (i1 < 0) || (i2 < 0) || (strings.Count(circular, attCore1) != 1) || (strings.Count(circular, attCore2) != 1) {
+		return "", fmt.Errorf("invalid input: the donor vector must contain attP1 and attP2 exactly once")
+	}
+	backbone := donor[(i2+len(attCore2))%len(donor):] + donor[:i1]
+	if i1 > i2 {
+		backbone = circular[i2+len(attCore2) : i1]
+	}
+	return product[j1:j2+len(attCore2)] + backbone, nil
+}
+
+// EntryClone returns the entry clone of a BP reaction of the attB PCR product with the `donor' vector (see
+// `BPRecombination') as a circular GenBank record named `name' with features for the attL cores and the ORF
+func (d GatewayDesign) EntryClone(name, donor string) (GenBankRecord, error) {
+	seq, err := BPRecombination(d.Product, donor)
+	if err != nil {
+		return GenBankRecord{}, err
+	}
+	offset := strings.Index(d.Product, attCore1)
+	end := strings.Index(d.Product, attCore2) + len(attCore2) - offset /* last position of the attL2 core */
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("entry clone of %s", d.ORF.Name),
+		Molecule:   "DNA",
+		Topology:   "circular",
+		Division:   "SYN",
+		Sequence:   seq,
+	}
+	r.Features = append(r.Features,
+		syntheticSource(len(seq)),
+		NewFeature("protein_bind", 1, len(attCore1), false, Qualifier{Key: "label", Value: "attL1 core"}),
+		NewFeature("CDS", d.orfStart-offset, d.orfStart-offset+len(d.ORF.Sequence)-1, false, Qualifier{Key: "label", Value: d.ORF.Name}),
+		NewFeature("protein_bind", end-len(attCore2)+1, end, true, Qualifier{Key: "label", Value: "attL2 core"}),
+	)
+	return r, nil
+}
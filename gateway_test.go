@@ -0,0 +1,99 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDesignGateway(t *testing.T) {
+	cases := []struct {
+		orf     string
+		opts    GatewayOptions
+		primers []string
+		err     error
+	}{
+		// native expression: the ORF has a start and a stop codon
+		{
+			orf:     testCDS,
+			opts:    GatewayOptions{},
+			primers: []string{"GGGGACAAGTTTGTACAAAAAAGCAGGCTATGGCTAAAGAGACCGTTCTGAAACG", "GGGGACCACTTTGTACAAGAAAGCTGGGTTTAGCGTTTCACCAGACCTTCTTTACG"},
+			err:     nil,
+		},
+		// a missing start codon is added behind the Kozak sequence
+		{
+			orf:     testCDS[3:],
+			opts:    GatewayOptions{Kozak: true},
+			primers: []string{"GGGGACAAGTTTGTACAAAAAAGCAGGCTGCCACCATGGCTAAAGAGACCGTTCTGAAACGTATCG", "GGGGACCACTTTGTACAAGAAAGCTGGGTTTAGCGTTTCACCAGACCTTCTTTACG"},
+			err:     nil,
+		},
+		// a missing stop codon is added behind the ORF
+		{
+			orf:     testCDS[:len(testCDS)-3],
+			opts:    GatewayOptions{ShineDalgarno: true},
+			primers: []string{"GGGGACAAGTTTGTACAAAAAAGCAGGCTAAGGAGATATACATATGGCTAAAGAGACCGTTCTGAAACG", "GGGGACCACTTTGTACAAGAAAGCTGGGTTTAGCGTTTCACCAGACCTTCTTTACG"},
+			err:     nil,
+		},
+		// fusions keep the ORF in frame with attB1 and attB2 and the stop codon is removed
+		{
+			orf:     testCDS[3:],
+			opts:    GatewayOptions{NTerminalFusion: true, CTerminalFusion: true},
+			primers: []string{"GGGGACAAGTTTGTACAAAAAAGCAGGCTCCGCTAAAGAGACCGTTCTGAAACGTATCG", "GGGGACCACTTTGTACAAGAAAGCTGGGTGGCGTTTCACCAGACCTTCTTTACG"},
+			err:     nil,
+		},
+		{orf: testCDS[1:], opts: GatewayOptions{}, primers: nil, err: errors.New("invalid input: the ORF has 128 nucleotides, which is not a multiple of 3")},
+		{orf: testCDS, opts: GatewayOptions{NTerminalFusion: true, Kozak: true}, primers: nil, err: errors.New("invalid input: a Kozak or Shine-Dalgarno sequence cannot be used with an N-terminal fusion")},
+	}
+	for _, c := range cases {
+		d, err := DesignGateway(FastaRecord{Name: "gene", Sequence: c.orf}, c.opts)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("DesignGateway(%+v) == %v, want %v\n", c.opts, err, c.err)
+		}
+		var primers []string
+		for _, p := range d.Primers {
+			primers = append(primers, p.Sequence)
+		}
+		if !reflect.DeepEqual(primers, c.primers) {
+			t.Errorf("DesignGateway(%+v) == %v, want %v\n", c.opts, primers, c.primers)
+		}
+	}
+
+	// the ORF of an N- and C-terminal fusion is in frame with attB1 (starting behind the padding) and attB2
+	d, _ := DesignGateway(FastaRecord{Name: "gene", Sequence: testCDS[3:]}, GatewayOptions{NTerminalFusion: true, CTerminalFusion: true})
+	if got, _ := Translate(d.Product[len(GatewayPadding):]); got != "TSLYKKAGSAKETVLKRIAEELRALGVKGLCTEVLRQLDELSRKEGLVKRHPAFLYKVVP" {
+		t.Errorf("DesignGateway() returned a product that translates to %v\n", got)
+	}
+}
+
+func TestBPRecombination(t *testing.T) {
+	d, err := DesignGateway(FastaRecord{Name: "gene", Sequence: testCDS}, GatewayOptions{})
+	if err != nil {
+		t.Fatalf("DesignGateway() == %v\n", err)
+	}
+	attP1, ccdB, attP2 := "CAACTTTGTACAAAAAAGCTGAACGAGA", "CCCCCCCCCC", "TCGTTCAGCTTTCTTGTACAAAGTTGGC"
+	entry := "TTTGTACAAAAAAGCAGGCT" + testCDS + "ACCCAGCTTTCTTGTACAAAGTTGGCGAATTCTTTTGGATCCAAAACAAC"
+	cases := []struct {
+		donor string
+		want  string
+		err   error
+	}{
+		{donor: "GGATCCAAAA" + attP1 + ccdB + attP2 + "GAATTCTTTT", want: entry, err: nil},
+		// the donor is circular
+		{donor: "AAACAAC" + attP1[4:] + ccdB + attP2 + "GAATTCTTTTGGATCCA", want: entry, err: nil},
+		{donor: "GGATCCAAAA" + attP1 + ccdB + "GAATTCTTTT", want: "", err: errors.New("invalid input: the donor vector must contain attP1 and attP2 exactly once")},
+	}
+	for _, c := range cases {
+		got, err := BPRecombination(d.Product, c.donor)
+		if got != c.want {
+			t.Errorf("BPRecombination(%v) == %v, want %v\n", c.donor, got, c.want)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("BPRecombination(%v) == %v, want %v\n", c.donor, err, c.err)
+		}
+	}
+	// the entry clone starts with the attL1 core and annotates the ORF
+	r, err := d.EntryClone("gene_entry", "GGATCCAAAA"+attP1+ccdB+attP2+"GAATTCTTTT")
+	if (err != nil) || (r.Sequence != entry) || (r.Topology != "circular") || (r.Features[2].Location != "21..149") || (r.Features[3].Location != "complement(156..170)") {
+		t.Errorf("EntryClone() == %v, %v, %v, %v, want %v, circular, 21..149, complement(156..170)\n", r.Sequence, r.Topology, r.Features, err, entry)
+	}
+}
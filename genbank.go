@@ -0,0 +1,489 @@
+package cloningprimer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FormatGenBank identifies the GenBank flat file format (records start with a LOCUS line)
+	FormatGenBank = "genbank"
+
+	// GenBankLineWidth is the maximum width of the lines that are written by `WriteGenBank' (except for lines with
+	// words that do not fit into a single line)
+	GenBankLineWidth = 79
+)
+
+// genBankDateRegexp matches the date at the end of a LOCUS line, e.g. 19-OCT-2026
+var genBankDateRegexp = regexp.MustCompile(`^\d{2}-[A-Z]{3}-\d{4}$`)
+
+// unquotedQualifiers holds the qualifiers whose values are written without quotes by `WriteGenBank'
+var unquotedQualifiers = map[string]bool{"codon_start": true, "transl_table": true, "number": true, "citation": true}
+
+// locationNumberRegexp matches the positions in a GenBank feature location (e.g. 1 and 120 in complement(<1..120))
+var locationNumberRegexp = regexp.MustCompile(`\d+`)
+
+// cdsQueryQualifiers holds the qualifiers that are searched by `GenBankRecord.FindCDS'
+var cdsQueryQualifiers = map[string]bool{"gene": true, "locus_tag": true, "gene_synonym": true, "protein_id": true, "label": true}
+
+// GenBankRecord holds a single record of a GenBank flat file
+type GenBankRecord struct {
+	Name       string    /* name from the LOCUS line, e.g. NM_000546 */
+	Accession  string    /* first accession number */
+	Definition string    /* description of the sequence */
+	Molecule   string    /* molecule type from the LOCUS line, e.g. DNA or mRNA */
+	Topology   string    /* "linear" or "circular" */
+	Division   string    /* GenBank division from the LOCUS line, e.g. SYN for synthetic constructs */
+	Date       string    /* modification date from the LOCUS line, e.g. 19-OCT-2026 */
+	Features   []Feature /* entries of the feature table in the order of the file */
+	Sequence   string    /* upper case nucleotide sequence */
+}
+
+// Feature holds an entry of the feature table of a GenBank (or EMBL) record
+type Feature struct {
+	Key        string      /* feature key, e.g. CDS, gene or misc_feature */
+	Location   string      /* location as written in the file, e.g. complement(join(10..90,120..300)) */
+	Start      int         /* first position (1-based) of the location */
+	End        int         /* last position (1-based, inclusive) of the location */
+	Complement bool        /* true if the feature is located on the bottom strand */
+	Joined     bool        /* true if the location consists of multiple parts (join or order) */
+	Qualifiers []Qualifier /* qualifiers in the order of the file */
+}
+
+// Qualifier holds a single qualifier of a `Feature', e.g. /gene="TP53"
+type Qualifier struct {
+	Key   string /* qualifier name without the leading '/', e.g. gene */
+	Value string /* unquoted value (empty for qualifiers without value like /pseudo) */
+}
+
+// Qualifier returns the value of the first qualifier named `key' (or an empty string if there is none)
+func (f Feature) Qualifier(key string) string {
+	for _, q := range f.Qualifiers {
+		if q.Key == key {
+			return q.Value
+		}
+	}
+	return ""
+}
+
+// Label returns a human readable name of the feature (its gene, locus tag, label or product qualifier, or its key)
+func (f Feature) Label() string {
+	for _, key := range []string{"gene", "locus_tag", "label", "product", "note"} {
+		if v := f.Qualifier(key); v != "" {
+			return v
+		}
+	}
+	return f.Key
+}
+
+// FindCDS returns the CDS feature of `r' whose gene, locus tag, gene synonym, protein ID or label qualifier equals
+// `query' (case-insensitive); an error is returned if no or more than one CDS matches
+func (r GenBankRecord) FindCDS(query string) (Feature, error) {
+	var matches []Feature
+	for _, f := range r.Features {
+		if f.Key != "CDS" {
+			continue
+		}
+		for _, q := range f.Qualifiers {
+			if cdsQueryQualifiers[q.Key] && strings.EqualFold(q.Value, query) {
+				matches = append(matches, f)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Feature{}, fmt.Errorf("invalid input: no CDS with gene name or locus tag %q in record %s", query, r.Name)
+	case 1:
+		return matches[0], nil
+	}
+	return Feature{}, fmt.Errorf("invalid input: %q matches %d CDS features in record %s", query, len(matches), r.Name)
+}
+
+// FeatureRegion returns the sequence in which feature `f' of `seq' runs from 5' to 3' (the reverse complement of `seq'
+// for features on the bottom strand) together with the positions that must be passed to `FindForward' (`seqStart',
+// counted from the 5' end) and `FindReverse' (`seqStart', counted from the 3' end) to design primers for exactly `f'
+func FeatureRegion(seq string, f Feature) (string, int, int, error) {
+	if (f.Start < 1) || (f.End < f.Start) || (f.End > len(seq)) {
+		return "", 0, 0, fmt.Errorf("invalid input: feature location %s does not fit the sequence (%d nucleotides)", f.Location, len(seq))
+	}
+	if f.Joined {
+		return "", 0, 0, fmt.Errorf("invalid input: feature location %s consists of multiple parts (use an mRNA record to clone a spliced CDS)", f.Location)
+	}
+	seq = strings.ToUpper(seq)
+	if f.Complement {
+		return reverseComplementIUPAC(seq), len(seq) - f.End + 1, f.Start, nil
+	}
+	return seq, f.Start, len(seq) - f.End + 1, nil
+}
+
+// syntheticSource returns the source feature of a synthetic construct of `length' nucleotides
+func syntheticSource(length int) Feature {
+	return NewFeature("source", 1, length, false, Qualifier{Key: "mol_type", Value: "other DNA"}, Qualifier{Key: "organism", Value: "synthetic DNA construct"})
+}
+
+// NewFeature returns a feature with the key `key' that is located at positions `start' to `end' (1-based, inclusive)
+// of the top strand (or the bottom strand if `complement' is true)
+func NewFeature(key string, start, end int, complement bool, qualifiers ...Qualifier) Feature {
+	loc := fmt.Sprintf("%d..%d", start, end)
+	if start == end {
+		loc = strconv.Itoa(start)
+	}
+	if complement {
+		loc = "complement(" + loc + ")"
+	}
+	return Feature{Key: key, Location: loc, Start: start, End: end, Complement: complement, Qualifiers: qualifiers}
+}
+
+// ParseGenBank parses all records from GenBank data in `r' (gzip-compressed data is decompressed transparently)
+func ParseGenBank(r io.Reader) ([]GenBankRecord, error) {
+	b, err := readData(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseGenBank(b, "input")
+}
+
+// ParseGenBankFromFile parses all records from a (possibly gzip-compressed) GenBank file
+func ParseGenBankFromFile(file string) ([]GenBankRecord, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return parseGenBank(b, file)
+}
+
+// parseGenBank parses the contents `b' of a GenBank file; `source' identifies the data in errors
+func parseGenBank(b []byte, source string) ([]GenBankRecord, error) {
+	var records []GenBankRecord
+	var record *GenBankRecord  /* the record that is currently parsed */
+	var section string         /* the current top level keyword, e.g. DEFINITION, FEATURES or ORIGIN */
+	var features featureParser /* parser of the feature table of the current record */
+	var seq []byte             /* sequence of the current record */
+	var line int               /* variable to keep track of the current line (for error messages) */
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r ")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		// a LOCUS line starts a new record, "//" ends it
+		if strings.HasPrefix(text, "LOCUS") {
+			fields := strings.Fields(text)
+			record = &GenBankRecord{Topology: "linear"}
+			if len(fields) > 1 {
+				record.Name = fields[1]
+			}
+			for i, field := range fields {
+				switch {
+				case (field == "linear") || (field == "circular"):
+					record.Topology = field
+				case (field == "bp") && (i+1 < len(fields)):
+					record.Molecule = fields[i+1]
+				case genBankDateRegexp.MatchString(field):
+					record.Date = field
+				case (i > 0) && (record.Topology == fields[i-1]) && (len(field) == 3) && (strings.ToUpper(field) == field):
+					record.Division = field
+				}
+			}
+			section, seq, features = "LOCUS", nil, featureParser{}
+			continue
+		}
+		if record == nil {
+			return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("expected a GenBank LOCUS line")}
+		}
+		if strings.HasPrefix(text, "//") {
+			if err := features.finish(); err != nil {
+				return nil, &ParseError{Source: source, Line: line, Err: err}
+			}
+			record.Features, record.Sequence = features.features, strings.ToUpper(string(seq))
+			records = append(records, *record)
+			record, section = nil, ""
+			continue
+		}
+
+		// top level keywords start in the first column, continuation lines are indented
+		if text[0] != ' ' {
+			if err := features.finish(); err != nil {
+				return nil, &ParseError{Source: source, Line: line, Err: err}
+			}
+			fields := strings.SplitN(text, " ", 2)
+			section = fields[0]
+			value := ""
+			if len(fields) == 2 {
+				value = strings.TrimSpace(fields[1])
+			}
+			switch section {
+			case "DEFINITION":
+				record.Definition = value
+			case "ACCESSION":
+				if f := strings.Fields(value); len(f) > 0 {
+					record.Accession = f[0]
+				}
+			}
+			continue
+		}
+
+		var err error
+		switch section {
+		case "DEFINITION":
+			record.Definition += " " + strings.TrimSpace(text)
+		case "FEATURES":
+			err = features.add(text)
+		case "ORIGIN":
+			seq, err = appendSequenceLine(seq, text)
+		}
+		if err != nil {
+			return nil, &ParseError{Source: source, Line: line, Err: err}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading GenBank data: %v", err)
+	}
+	if record != nil {
+		return nil, &ParseError{Source: source, Line: line, Err: fmt.Errorf("record %s is not terminated by '//'", record.Name)}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("invalid input: no GenBank records found in %s", source)
+	}
+	logf("parsed %d GenBank record(s) from '%s'\n", len(records), source)
+	return records, nil
+}
+
+// featureParser collects the features of a GenBank or EMBL feature table line by line
+type featureParser struct {
+	features  []Feature  /* features that were parsed completely */
+	feature   *Feature   /* the feature that is currently parsed */
+	qualifier *Qualifier /* the qualifier that is currently parsed */
+}
+
+// add parses a line of a feature table; feature keys start in column 6, locations and qualifiers in column 22 (the
+// "FT" prefix of EMBL lines must be replaced by spaces)
+func (p *featureParser) add(text string) error {
+	if (len(text) > 5) && (text[5] != ' ') {
+		if err := p.finish(); err != nil {
+			return err
+		}
+		fields := strings.Fields(text)
+		p.feature = &Feature{Key: fields[0]}
+		if len(fields) > 1 {
+			p.feature.Location = strings.Join(fields[1:], "")
+		}
+		return nil
+	}
+	if p.feature == nil {
+		return fmt.Errorf("expected a feature key")
+	}
+	value := strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(value, "/"):
+		parts := strings.SplitN(value[1:], "=", 2)
+		p.feature.Qualifiers = append(p.feature.Qualifiers, Qualifier{Key: parts[0]})
+		p.qualifier = &p.feature.Qualifiers[len(p.feature.Qualifiers)-1]
+		if len(parts) == 2 {
+			p.qualifier.Value = parts[1]
+		}
+	case p.qualifier == nil:
+		p.feature.Location += value
+	case p.qualifier.Key == "translation":
+		p.qualifier.Value += value
+	default:
+		p.qualifier.Value += " " + value
+	}
+	return nil
+}
+
+// finish parses the location of the current feature (if any) and adds it to the parsed features
+func (p *featureParser) finish() error {
+	defer func() { p.feature, p.qualifier = nil, nil }()
+	if p.feature == nil {
+		return nil
+	}
+	if err := parseLocation(p.feature); err != nil {
+		return err
+	}
+	for i := range p.feature.Qualifiers {
+		p.feature.Qualifiers[i].Value = unquoteQualifier(p.feature.Qualifiers[i].Value)
+	}
+	p.features = append(p.features, *p.feature)
+	return nil
+}
+
+// appendSequenceLine appends the nucleotides of a sequence line of a GenBank or EMBL file to `seq' (white space and
+// position numbers are ignored, IUPAC codes like N are accepted)
+func appendSequenceLine(seq []byte, text string) ([]byte, error) {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case (text[i] == ' ') || ((text[i] >= '0') && (text[i] <= '9')):
+			continue
+		case !IsIUPACNucleotide(text[i]):
+			return seq, &NucleotideError{Letter: rune(text[i]), Position: len(seq) + 1, IUPAC: true}
+		}
+		seq = append(seq, text[i])
+	}
+	return seq, nil
+}
+
+// parseLocation sets the start, end, strand and join fields of `f' based on its location string
+func parseLocation(f *Feature) error {
+	loc := f.Location
+	if strings.HasPrefix(loc, "complement(") && strings.HasSuffix(loc, ")") {
+		f.Complement = true
+		loc = loc[len("complement(") : len(loc)-1]
+	}
+	f.Joined = strings.HasPrefix(loc, "join(") || strings.HasPrefix(loc, "order(")
+	if f.Joined && strings.Contains(loc, "complement(") {
+		f.Complement = true /* e.g. join(complement(4918..5163),complement(2691..4571)) */
+	}
+	numbers := locationNumberRegexp.FindAllString(loc, -1)
+	if (len(numbers) == 0) || strings.Contains(loc, ":") {
+		return fmt.Errorf("unsupported feature location %q", f.Location)
+	}
+	f.Start, f.End = -1, -1
+	for _, n := range numbers {
+		pos, err := strconv.Atoi(n)
+		if err != nil {
+			return fmt.Errorf("invalid feature location %q: %v", f.Location, err)
+		}
+		if (f.Start == -1) || (pos < f.Start) {
+			f.Start = pos
+		}
+		if pos > f.End {
+			f.End = pos
+		}
+	}
+	return nil
+}
+
+// unquoteQualifier removes the quotes around a qualifier value and replaces escaped quotes ("") by single quotes
+func unquoteQualifier(v string) string {
+	if (len(v) >= 2) && strings.HasPrefix(v, "\"") && strings.HasSuffix(v, "\"") {
+		v = v[1 : len(v)-1]
+	}
+	return strings.Replace(v, "\"\"", "\"", -1)
+}
+
+// WriteGenBank writes `records' to `w' in GenBank flat file format; the files can be opened by common sequence
+// editors (e.g. SnapGene, ApE or Benchling) and are parsed by `ParseGenBank'; an empty molecule type, topology or
+// division is written as DNA, linear or UNK
+func WriteGenBank(w io.Writer, records []GenBankRecord) error {
+	var sb strings.Builder
+	for _, r := range records {
+		molecule, topology, division := r.Molecule, r.Topology, r.Division
+		if molecule == "" {
+			molecule = "DNA"
+		}
+		if topology == "" {
+			topology = "linear"
+		}
+		if division == "" {
+			division = "UNK" /* keeps the date in its column */
+		}
+		locus := fmt.Sprintf("LOCUS       %-16s %11d bp    %-6s  %-8s %s %s", r.Name, len(r.Sequence), molecule, topology, division, r.Date)
+		sb.WriteString(strings.TrimRight(locus, " ") + "\n")
+		definition := r.Definition
+		if definition == "" {
+			definition = "."
+		}
+		writeWrapped(&sb, "DEFINITION  ", strings.Repeat(" ", 12), definition, true)
+		accession := r.Accession
+		if accession == "" {
+			accession = "."
+		}
+		sb.WriteString("ACCESSION   " + accession + "\n")
+		sb.WriteString("KEYWORDS    .\n")
+
+		// feature keys start in column 6, locations and qualifiers in column 22
+		sb.WriteString("FEATURES             Location/Qualifiers\n")
+		writeFeatures(&sb, "     ", r.Features)
+
+		// the sequence is written in lines of 60 nucleotides in blocks of 10
+		sb.WriteString("ORIGIN\n")
+		seq := strings.ToLower(r.Sequence)
+		for i := 0; i < len(seq); i += 60 {
+			sb.WriteString(fmt.Sprintf("%9d", i+1))
+			for j := i; (j < i+60) && (j < len(seq)); j += 10 {
+				end := j + 10
+				if end > len(seq) {
+					end = len(seq)
+				}
+				sb.WriteString(" " + seq[j:end])
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("//\n")
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("error writing GenBank data: %v", err)
+	}
+	return nil
+}
+
+// WriteGenBankToFile writes `records' to `file' in GenBank flat file format (see `WriteGenBank')
+func WriteGenBankToFile(file string, records []GenBankRecord) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	err = WriteGenBank(f, records)
+	if closeErr := f.Close(); (err == nil) && (closeErr != nil) {
+		err = fmt.Errorf("error closing file: %v", closeErr)
+	}
+	return err
+}
+
+// writeFeatures writes the feature table of a GenBank or EMBL record to `sb'; every line starts with `prefix' (five
+// spaces for GenBank and "FT   " for EMBL files)
+func writeFeatures(sb *strings.Builder, prefix string, features []Feature) {
+	indent := prefix + strings.Repeat(" ", 16)
+	for _, f := range features {
+		sb.WriteString(fmt.Sprintf("%s%-16s%s\n", prefix, f.Key, f.Location))
+		for _, q := range f.Qualifiers {
+			switch {
+			case q.Value == "":
+				sb.WriteString(indent + "/" + q.Key + "\n")
+			case unquotedQualifiers[q.Key]:
+				sb.WriteString(indent + "/" + q.Key + "=" + q.Value + "\n")
+			default:
+				value := "/" + q.Key + "=\"" + strings.Replace(q.Value, "\"", "\"\"", -1) + "\""
+				writeWrapped(sb, indent, indent, value, q.Key != "translation")
+			}
+		}
+	}
+}
+
+// writeWrapped writes `text' to `sb' in lines of at most `GenBankLineWidth' characters; the first line starts with
+// `first', all other lines with `indent'; lines are broken at spaces if `words' is true and after any letter otherwise
+func writeWrapped(sb *strings.Builder, first, indent, text string, words bool) {
+	prefix := first
+	for {
+		width := GenBankLineWidth - len(prefix)
+		if len(text) <= width {
+			sb.WriteString(prefix + text + "\n")
+			return
+		}
+		cut := width
+		if words {
+			cut = strings.LastIndex(text[:width+1], " ")
+			if cut <= 0 {
+				cut = strings.Index(text, " ") /* the first word does not fit into a line */
+			}
+			if cut <= 0 {
+				sb.WriteString(prefix + text + "\n")
+				return
+			}
+		}
+		sb.WriteString(prefix + text[:cut] + "\n")
+		text, prefix = strings.TrimLeft(text[cut:], " "), indent
+	}
+}
@@ -0,0 +1,173 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCaseFeatureRegion struct {
+	in     string
+	seq    string
+	startF int
+	startR int
+	err    error
+}
+
+func TestParseGenBankFromFile(t *testing.T) {
+	records, err := ParseGenBankFromFile("tests/genbank1.gb")
+	if err != nil {
+		t.Fatalf("ParseGenBankFromFile(tests/genbank1.gb) == %v, want <nil>\n", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ParseGenBankFromFile(tests/genbank1.gb) returned %d records, want 1\n", len(records))
+	}
+	r := records[0]
+
+	// test header data
+	got := []string{r.Name, r.Accession, r.Definition, r.Molecule, r.Topology, r.Division, r.Date}
+	want := []string{"pTEST1", "TEST0001", "Test plasmid with two coding sequences for cloningPrimer unit tests.", "DNA", "circular", "SYN", "19-OCT-2026"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGenBankFromFile(tests/genbank1.gb) header == %q, want %q\n", got, want)
+	}
+	if (len(r.Sequence) != 124) || (r.Sequence[:10] != "CCGGAATTCC") {
+		t.Errorf("ParseGenBankFromFile(tests/genbank1.gb) sequence == %s, want 124 upper case nucleotides\n", r.Sequence)
+	}
+
+	// test the feature table
+	if len(r.Features) != 6 {
+		t.Fatalf("ParseGenBankFromFile(tests/genbank1.gb) returned %d features, want 6\n", len(r.Features))
+	}
+	cds := r.Features[2]
+	wantCDS := Feature{Key: "CDS", Location: "11..40", Start: 11, End: 40, Qualifiers: []Qualifier{
+		{Key: "gene", Value: "geneA"},
+		{Key: "locus_tag", Value: "TST_0001"},
+		{Key: "product", Value: "test protein A with a long product name that spans two lines"},
+		{Key: "translation", Value: "MKPGFKGPK"},
+	}}
+	if !reflect.DeepEqual(cds, wantCDS) {
+		t.Errorf("ParseGenBankFromFile(tests/genbank1.gb) feature 3 == %+v, want %+v\n", cds, wantCDS)
+	}
+	if f := r.Features[3]; !f.Complement || f.Joined || (f.Start != 61) || (f.End != 90) || (f.Label() != "geneB") {
+		t.Errorf("ParseGenBankFromFile(tests/genbank1.gb) feature 4 == %+v, want complement(61..90) of geneB\n", f)
+	}
+	if f := r.Features[4]; (f.Location != "join(1..5,100..110)") || !f.Joined || (f.Start != 1) || (f.End != 110) {
+		t.Errorf("ParseGenBankFromFile(tests/genbank1.gb) feature 5 == %+v, want join(1..5,100..110)\n", f)
+	}
+
+	// test errors
+	_, err = ParseGenBankFromFile("tests/genbank2.gb")
+	wantErr := "tests/genbank2.gb:3: invalid input x at position 4, expected sequence of lower or upper case A,T,C,G or IUPAC codes"
+	if (err == nil) || (err.Error() != wantErr) {
+		t.Errorf("ParseGenBankFromFile(tests/genbank2.gb) == %v, want %v\n", err, wantErr)
+	}
+	_, err = ParseGenBankFromFile("tests/fasta1.fa")
+	wantErr = "tests/fasta1.fa:1: expected a GenBank LOCUS line"
+	if (err == nil) || (err.Error() != wantErr) {
+		t.Errorf("ParseGenBankFromFile(tests/fasta1.fa) == %v, want %v\n", err, wantErr)
+	}
+}
+
+func TestFeatureRegion(t *testing.T) {
+	records, err := ParseGenBankFromFile("tests/genbank1.gb")
+	if err != nil {
+		t.Fatalf("ParseGenBankFromFile(tests/genbank1.gb) == %v, want <nil>\n", err)
+	}
+	r := records[0]
+	cases := []testCaseFeatureRegion{
+		// CDS on the top strand (found by gene name)
+		{in: "geneA", seq: r.Sequence, startF: 11, startR: 85, err: nil},
+		// CDS on the bottom strand (found by locus tag, case-insensitive)
+		{in: "tst_0002", seq: reverseComplementIUPAC(r.Sequence), startF: 35, startR: 61, err: nil},
+		// CDS with multiple parts
+		{in: "geneC", seq: "", err: errors.New("invalid input: feature location join(1..5,100..110) consists of multiple parts (use an mRNA record to clone a spliced CDS)")},
+		// unknown CDS
+		{in: "geneD", seq: "", err: errors.New("invalid input: no CDS with gene name or locus tag \"geneD\" in record pTEST1")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		var seq string
+		var startF, startR int
+		f, err := r.FindCDS(c.in)
+		if err == nil {
+			seq, startF, startR, err = FeatureRegion(r.Sequence, f)
+		}
+
+		// test similarity of expected and received value
+		if (seq != c.seq) || (startF != c.startF) || (startR != c.startR) {
+			t.Errorf("FeatureRegion(%v) == %v, %d, %d, want %v, %d, %d\n", c.in, seq, startF, startR, c.seq, c.startF, c.startR)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("FeatureRegion(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+
+	// the forward primer of a CDS on the bottom strand starts with its start codon
+	seq, startF, startR, _ := FeatureRegion(r.Sequence, r.Features[3])
+	primer, err := FindForward(seq, "GAATTC", startF, 12, 4, false)
+	if (err != nil) || (primer[10:] != "ATGCCCGGGAAA") {
+		t.Errorf("FindForward() for geneB == %v, %v, want a primer ending in ATGCCCGGGAAA\n", primer, err)
+	}
+	primer, err = FindReverse(seq, "GGATCC", startR, 12, 4, false)
+	if (err != nil) || (primer[10:] != "CTAAAATTTCCC") {
+		t.Errorf("FindReverse() for geneB == %v, %v, want a primer ending in CTAAAATTTCCC\n", primer, err)
+	}
+}
+
+func TestWriteGenBank(t *testing.T) {
+	r := GenBankRecord{
+		Name:     "test",
+		Division: "SYN",
+		Sequence: "ATGGCCGCGTTGACGAGTGAGCATAGATGGCCGCGTTGACGAGTGAGCATAGATGGCCGCGTTGACGA",
+		Features: []Feature{NewFeature("primer_bind", 1, 10, true, Qualifier{Key: "label", Value: "test_R"}, Qualifier{Key: "pseudo"},
+			Qualifier{Key: "note", Value: "a very long note that contains \"quotes\" and does not fit into a single line of the feature table"})},
+	}
+	want := `LOCUS       test                      68 bp    DNA     linear   SYN
+DEFINITION  .
+ACCESSION   .
+KEYWORDS    .
+FEATURES             Location/Qualifiers
+     primer_bind     complement(1..10)
+                     /label="test_R"
+                     /pseudo
+                     /note="a very long note that contains ""quotes"" and does
+                     not fit into a single line of the feature table"
+ORIGIN
+        1 atggccgcgt tgacgagtga gcatagatgg ccgcgttgac gagtgagcat agatggccgc
+       61 gttgacga
+//
+`
+	var sb strings.Builder
+	if err := WriteGenBank(&sb, []GenBankRecord{r}); (err != nil) || (sb.String() != want) {
+		t.Errorf("WriteGenBank() == %v, %v, want %v, <nil>\n", sb.String(), err, want)
+	}
+
+	// records without a division are written with the division UNK, so that the date stays in its column
+	sb.Reset()
+	r = GenBankRecord{Name: "test", Date: "19-OCT-2026", Sequence: "ATGGCC"}
+	wantLocus := "LOCUS       test                       6 bp    DNA     linear   UNK 19-OCT-2026\n"
+	if err := WriteGenBank(&sb, []GenBankRecord{r}); (err != nil) || !strings.HasPrefix(sb.String(), wantLocus) {
+		t.Errorf("WriteGenBank() == %v, %v, want %v..., <nil>\n", sb.String(), err, wantLocus)
+	}
+	if got, err := ParseGenBank(strings.NewReader(sb.String())); (err != nil) || (got[0].Division != "UNK") || (got[0].Date != r.Date) {
+		t.Errorf("ParseGenBank(WriteGenBank(%+v)) == %+v, %v, want division UNK and date %v, <nil>\n", r, got, err, r.Date)
+	}
+
+	// written records are parsed again without loss of information
+	records, err := ParseGenBankFromFile("tests/genbank1.gb")
+	if err != nil {
+		t.Fatalf("ParseGenBankFromFile(tests/genbank1.gb) == %v, want <nil>\n", err)
+	}
+	sb.Reset()
+	if err = WriteGenBank(&sb, records); err != nil {
+		t.Fatalf("WriteGenBank(tests/genbank1.gb) == %v, want <nil>\n", err)
+	}
+	got, err := ParseGenBank(strings.NewReader(sb.String()))
+	if !reflect.DeepEqual(got, records) || (err != nil) {
+		t.Errorf("ParseGenBank(WriteGenBank(tests/genbank1.gb)) == %+v, %v, want %+v, <nil>\n", got, err, records)
+	}
+}
@@ -0,0 +1,354 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// GibsonMinOverlap gives the default minimum length of the overlaps between adjacent fragments of a Gibson assembly
+	GibsonMinOverlap = 20
+
+	// GibsonMaxOverlap gives the default maximum length of the overlaps between adjacent fragments of a Gibson assembly
+	GibsonMaxOverlap = 40
+
+	// GibsonOverlapTm gives the default minimum Tm (in °C, see `CalculateTmNN') of the overlaps of a Gibson assembly
+	GibsonOverlapTm = 50.0
+
+	// AssemblyAnnealTm gives the default Tm (in °C, see `CalculateTmNN') that the annealing parts of assembly primers
+	// should reach
+	AssemblyAnnealTm = 58.0
+
+	// MaxHairpinStem gives the length of the longest hairpin stem that is tolerated in an overlap
+	MaxHairpinStem = 5
+)
+
+// GibsonOptions holds the parameters of `DesignGibson'; zero values select the respective defaults
+type GibsonOptions struct {
+	Enzymes    []RestrictEnzyme /* one or two enzymes that linearize the (circular) vector (none if it is linear) */
+	MinOverlap int              /* minimum length of the overlaps (defaults to `GibsonMinOverlap') */
+	MaxOverlap int              /* maximum length of the overlaps (defaults to `GibsonMaxOverlap') */
+	OverlapTm  float64          /* minimum Tm of the overlaps in °C (defaults to `GibsonOverlapTm') */
+	AnnealTm   float64          /* Tm of the annealing parts of the primers in °C (defaults to `AssemblyAnnealTm') */
+}
+
+// AssemblyPrimer is a primer that amplifies a fragment of an assembly and adds the overlap with a neighboring fragment
+type AssemblyPrimer struct {
+	Name     string  /* name of the primer, e.g. insert_F */
+	Sequence string  /* sequence of the primer (5' to 3') */
+	Tail     int     /* number of 5' nucleotides that do not bind to the amplified fragment */
+	Tm       float64 /* Tm of the annealing part of the primer in °C */
+}
+
+// AssemblyJunction describes the overlap between two adjacent fragments of an assembly
+type AssemblyJunction struct {
+	Left        string  /* name of the upstream fragment */
+	Right       string  /* name of the downstream fragment */
+	Overlap     string  /* sequence of the overlap (top strand) */
+	Tm          float64 /* Tm of the overlap in °C */
+	Unique      bool    /* true if the overlap occurs only once in the assembled product (on either strand) */
+	HairpinStem int     /* length of the longest hairpin stem that the overlap can form */
+}
+
+// Assembly holds the primers and the product of an assembly of a vector backbone and one or more inserts
+type Assembly struct {
+	Fragments []FastaRecord      /* the vector backbone followed by the inserts (in the order of the product) */
+	Primers   []AssemblyPrimer   /* a forward and a reverse primer per insert */
+	Junctions []AssemblyJunction /* the overlaps of the junctions, starting with the one between vector and first insert */
+	Product   string             /* sequence of the circular product, starting with the vector backbone */
+	Warnings  []string           /* overlaps or primers that do not meet all requirements */
+}
+
+// Record returns the circular product of the assembly as a GenBank record named `name' with a feature per fragment
+// and overlap
+func (a Assembly) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("assembly of %d fragments", len(a.Fragments)),
+		Molecule:   "DNA",
+		Topology:   "circular",
+		Division:   "SYN",
+		Sequence:   a.Product,
+	}
+	r.Features = append(r.Features, syntheticSource(len(a.Product)))
+	start := 1
+	for _, f := range a.Fragments {
+		r.Features = append(r.Features, NewFeature("misc_feature", start, start+len(f.Sequence)-1, false, Qualifier{Key: "label", Value: f.Name}))
+		start += len(f.Sequence)
+	}
+	for _, j := range a.Junctions {
+		if i := strings.Index(a.Product+a.Product, j.Overlap); (i >= 0) && (i+len(j.Overlap) <= len(a.Product)) {
+			r.Features = append(r.Features, NewFeature("misc_feature", i+1, i+len(j.Overlap), false,
+				Qualifier{Key: "label", Value: fmt.Sprintf("overlap %s/%s", j.Left, j.Right)}, Qualifier{Key: "note", Value: fmt.Sprintf("Tm %.1f C", j.Tm)}))
+		}
+	}
+	return r
+}
+
+// LinearizeVector returns the backbone of the circular `vector' after a digest with one or two restriction `enzymes':
+// the backbone starts at the cut of the last enzyme and ends at the cut of the first enzyme (wrapping around the origin
+// of `vector'), i.e. inserts are placed between the first and the last site; every enzyme must cut `vector' exactly
+// once and its cut positions must be known (e.g. from REBASE data or the default enzymes)
+func LinearizeVector(vector string, enzymes ...RestrictEnzyme) (string, error) {
+	if (len(enzymes) < 1) || (len(enzymes) > 2) {
+		return "", fmt.Errorf("invalid input: a vector is linearized with one or two enzymes, not %d", len(enzymes))
+	}
+	seq, err := ValidateSequence([]byte(vector))
+	if err != nil {
+		return "", fmt.Errorf("invalid vector sequence: %v", err)
+	}
+
+	// find the top strand cuts (0-based index of the first nucleotide after the cut) that end the backbone (in front
+	// of the insert) and that start the backbone (behind the insert)
+	var tops []int
+	for _, e := range enzymes {
+		if !e.CutKnown {
+			return "", fmt.Errorf("invalid input: the cut positions of %s are unknown (use REBASE data or the default enzymes)", e.Name)
+		}
+		_, top, _, err := vectorCut(seq, e)
+		if err != nil {
+			return "", err
+		}
+		tops = append(tops, top)
+	}
+	start, end := tops[len(tops)-1], tops[0]
+	start, end = ((start%len(seq))+len(seq))%len(seq), ((end%len(seq))+len(seq))%len(seq)
+	if (len(enzymes) == 2) && (start == end) {
+		return "", fmt.Errorf("invalid input: %s and %s cut the vector at the same position", enzymes[0].Name, enzymes[1].Name)
+	}
+	if end <= start {
+		end += len(seq)
+	}
+	return (seq + seq)[start:end], nil
+}
+
+// vectorCut returns the 0-based position of the only site of `e' in the circular `seq' and the positions of the cuts
+// in the top and the bottom strand (0-based index of the first nucleotide after the cut, counted on the top strand;
+// only valid if `e.CutKnown' is set)
+func vectorCut(seq string, e RestrictEnzyme) (int, int, int, error) {
+	site := strings.ToUpper(e.RecognitionSite)
+	circular := seq + seq[:len(site)-1]
+	sites := FindSites(circular, site)
+	if len(sites) != 1 {
+		return 0, 0, 0, fmt.Errorf("invalid input: %s must cut the vector exactly once, found %d site(s)", e.Name, len(sites))
+	}
+	i := sites[0] - 1
+	if (site != reverseComplementIUPAC(site)) && !matchesIUPAC(circular[i:i+len(site)], site) {
+		return i, i + len(site) - e.BottomCut, i + len(site) - e.TopCut, nil /* the site is on the bottom strand */
+	}
+	return i, i + e.TopCut, i + e.BottomCut, nil
+}
+
+// DesignGibson designs primers for a Gibson (or NEBuilder HiFi) assembly of a linear `vector' (or a circular one that
+// is linearized with `opts.Enzymes', see `LinearizeVector', sites that overlap Dam or Dcm methylation are reported in
+// the warnings) and one or more `inserts' that are placed in the given order between the end and the start of the
+// vector; the overlaps with the vector are added to the insert primers, the overlaps between two inserts are split
+// between their primers; for every junction, the shortest overlap between `opts.MinOverlap' and `opts.MaxOverlap'
+// is chosen whose Tm reaches `opts.OverlapTm' and that is unique in the product and does not form a hairpin stem of
+// more than `MaxHairpinStem' nucleotides; if no overlap meets all requirements, the shortest overlap that reaches the
+// Tm is used and a warning is added to the result
+func DesignGibson(vector FastaRecord, inserts []FastaRecord, opts GibsonOptions) (Assembly, error) {
+	if opts.MinOverlap == 0 {
+		opts.MinOverlap = GibsonMinOverlap
+	}
+	if opts.MaxOverlap == 0 {
+		opts.MaxOverlap = GibsonMaxOverlap
+	}
+	if opts.OverlapTm == 0 {
+		opts.OverlapTm = GibsonOverlapTm
+	}
+	if opts.AnnealTm == 0 {
+		opts.AnnealTm = AssemblyAnnealTm
+	}
+	if (opts.MinOverlap < MinimumPrimerLength) || (opts.MaxOverlap < opts.MinOverlap) {
+		return Assembly{}, fmt.Errorf("invalid input: overlaps of %d-%d nucleotides, expected a minimum >= %d and a maximum >= the minimum", opts.MinOverlap, opts.MaxOverlap, MinimumPrimerLength)
+	}
+	var warnings []string
+	if len(opts.Enzymes) > 0 {
+		backbone, err := LinearizeVector(vector.Sequence, opts.Enzymes...)
+		if err != nil {
+			return Assembly{}, err
+		}
+		warnings = methylationWarnings("vector", vector.Sequence, opts.Enzymes...)
+		vector.Sequence = backbone
+	}
+	a, err := newAssembly(vector, inserts)
+	if err != nil {
+		return Assembly{}, err
+	}
+	a.Warnings = warnings
+
+	// choose the overlap of every junction (fragment k and its successor); the vector backbone is not amplified, so
+	// all overlaps with it are added to the insert primers
+	n := len(a.Fragments)
+	tailF, tailR := make([]string, n), make([]string, n) /* tails of the forward and reverse primer of every fragment */
+	for k := 0; k < n; k++ {
+		left, right := a.Fragments[k], a.Fragments[(k+1)%n]
+		var best *AssemblyJunction
+		var bestLeft int
+		for l := opts.MinOverlap; l <= opts.MaxOverlap; l++ {
+			fromLeft := l / 2 /* number of nucleotides of the overlap from `left' */
+			switch k {
+			case 0:
+				fromLeft = l
+			case n - 1:
+				fromLeft = 0
+			}
+			if (fromLeft > len(left.Sequence)) || (l-fromLeft > len(right.Sequence)) {
+				break
+			}
+			j := a.junction(left.Name, right.Name, left.Sequence[len(left.Sequence)-fromLeft:]+right.Sequence[:l-fromLeft])
+			if j.Tm < opts.OverlapTm {
+				continue
+			}
+			if best == nil {
+				best, bestLeft = &j, fromLeft
+			}
+			if j.Unique && (j.HairpinStem <= MaxHairpinStem) {
+				best, bestLeft = &j, fromLeft
+				break
+			}
+		}
+		if best == nil {
+			return Assembly{}, fmt.Errorf("invalid input: no overlap of %d-%d nucleotides between %s and %s reaches a Tm of %.1f°C", opts.MinOverlap, opts.MaxOverlap, left.Name, right.Name, opts.OverlapTm)
+		}
+		a.addJunction(*best)
+		tailF[(k+1)%n] = best.Overlap[:bestLeft]
+		tailR[k] = reverseComplementIUPAC(best.Overlap[bestLeft:])
+	}
+	a.addPrimers(tailF, tailR, opts.AnnealTm)
+	return a, nil
+}
+
+// newAssembly returns an assembly of the validated `vector' backbone and `inserts' without primers and junctions
+func newAssembly(vector FastaRecord, inserts []FastaRecord) (Assembly, error) {
+	if len(inserts) == 0 {
+		return Assembly{}, fmt.Errorf("invalid input: an assembly needs at least one insert")
+	}
+	var a Assembly
+	for i, f := range append([]FastaRecord{vector}, inserts...) {
+		seq, err := ValidateSequence([]byte(f.Sequence))
+		if err != nil {
+			return Assembly{}, fmt.Errorf("invalid sequence of fragment %d: %v", i+1, err)
+		}
+		switch {
+		case len(seq) < MinimumPrimerLength:
+			return Assembly{}, fmt.Errorf("invalid input: fragment %d has %d nucleotides, expected at least %d", i+1, len(seq), MinimumPrimerLength)
+		case (f.Name == "") && (i == 0):
+			f.Name = "vector"
+		case f.Name == "":
+			f.Name = fmt.Sprintf("insert%d", i)
+		}
+		f.Sequence = seq
+		a.Fragments = append(a.Fragments, f)
+		a.Product += seq
+	}
+	return a, nil
+}
+
+// junction returns the junction between the fragments `left' and `right' with the overlap `s'
+func (a Assembly) junction(left, right, s string) AssemblyJunction {
+	tm, _ := CalculateTmNN(s)
+	return AssemblyJunction{Left: left, Right: right, Overlap: s, Tm: tm, Unique: countCircular(a.Product, s) == 1, HairpinStem: hairpinStem(s)}
+}
+
+// addJunction adds the junction `j' to `a' and warns about its problems
+func (a *Assembly) addJunction(j AssemblyJunction) {
+	a.Junctions = append(a.Junctions, j)
+	if !j.Unique {
+		a.Warnings = append(a.Warnings, fmt.Sprintf("the overlap %s between %s and %s is not unique in the product", j.Overlap, j.Left, j.Right))
+	}
+	if j.HairpinStem > MaxHairpinStem {
+		a.Warnings = append(a.Warnings, fmt.Sprintf("the overlap %s between %s and %s can form a hairpin with a stem of %d nucleotides", j.Overlap, j.Left, j.Right, j.HairpinStem))
+	}
+}
+
+// addPrimers adds a forward and a reverse primer for every insert of `a' (all fragments except for the vector
+// backbone); the annealing parts are the shortest ones that reach `annealTm' and the primers start with the tails
+// `tailF' and `tailR' of the respective fragment
+func (a *Assembly) addPrimers(tailF, tailR []string, annealTm float64) {
+	for k, f := range a.Fragments[1:] {
+		k++
+		primers, warnings := tailedPrimers(annealTm, tailedPrimer{f.Name + "_F", f.Sequence, tailF[k]}, tailedPrimer{f.Name + "_R", reverseComplementIUPAC(f.Sequence), tailR[k]})
+		a.Primers = append(a.Primers, primers...)
+		a.Warnings = append(a.Warnings, warnings...)
+	}
+}
+
+// tailedPrimer describes a primer whose annealing part is taken from the 5' end of `template' and that carries `tail'
+// at its 5' end
+type tailedPrimer struct {
+	name     string
+	template string
+	tail     string
+}
+
+// tailedPrimers returns the `primers' with annealing parts that reach `annealTm' (see `annealingPart') and a warning
+// for every primer whose annealing part does not
+func tailedPrimers(annealTm float64, primers ...tailedPrimer) ([]AssemblyPrimer, []string) {
+	var result []AssemblyPrimer
+	var warnings []string
+	for _, p := range primers {
+		anneal, tm := annealingPart(p.template, annealTm)
+		if tm < annealTm {
+			warnings = append(warnings, annealingWarning(p.name, tm))
+		}
+		result = append(result, AssemblyPrimer{Name: p.name, Sequence: p.tail + anneal, Tail: len(p.tail), Tm: tm})
+	}
+	return result, warnings
+}
+
+// annealingWarning returns the warning for the primer `name' whose annealing part only reaches a Tm of `tm'
+func annealingWarning(name string, tm float64) string {
+	return fmt.Sprintf("the annealing part of primer %s only reaches a Tm of %.1f°C", name, tm)
+}
+
+// annealingPart returns the shortest 5' part of `template' (between `MinimumPrimerLength' and `MaximumPrimerLength'
+// nucleotides) whose Tm reaches `tm', or the longest part if none does, and its Tm
+func annealingPart(template string, tm float64) (string, float64) {
+	var part string
+	var partTm float64
+	for l := MinimumPrimerLength; (l <= MaximumPrimerLength) && (l <= len(template)); l++ {
+		part = template[:l]
+		partTm, _ = CalculateTmNN(part)
+		if partTm >= tm {
+			break
+		}
+	}
+	return part, partTm
+}
+
+// countCircular returns the number of occurrences of `s' on both strands of the circular sequence `seq'
+func countCircular(seq, s string) int {
+	if len(s) > len(seq) {
+		return 0
+	}
+	circular := seq + seq[:len(s)-1]
+	n := len(FindSites(circular, s))
+	if reverseComplementIUPAC(s) == s {
+		return n * 2
+	}
+	return n
+}
+
+// hairpinStem returns the length of the longest stem of a hairpin that `seq' can form, i.e. of a part of `seq' that is
+// complementary to a downstream part, separated by a loop of at least 3 nucleotides
+func hairpinStem(seq string) int {
+	var best int
+	for i := 0; i < len(seq); i++ {
+		for j := len(seq) - 1; j > i; j-- {
+			k := 0
+			for (j-k)-(i+k)-1 >= 3 {
+				c, err := Complement(seq[i+k])
+				if (err != nil) || (c != seq[j-k]) {
+					break
+				}
+				k++
+			}
+			if k > best {
+				best = k
+			}
+		}
+	}
+	return best
+}
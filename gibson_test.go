@@ -0,0 +1,139 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	testEcoRI = RestrictEnzyme{Name: "EcoRI", RecognitionSite: "GAATTC", TopCut: 1, BottomCut: 5, CutKnown: true}
+	testBamHI = RestrictEnzyme{Name: "BamHI", RecognitionSite: "GGATCC", TopCut: 1, BottomCut: 5, CutKnown: true}
+)
+
+func TestLinearizeVector(t *testing.T) {
+	records, err := ParseFastaFromFile("tests/gibson1.fa")
+	if err != nil {
+		t.Fatalf("ParseFastaFromFile(tests/gibson1.fa) == %v\n", err)
+	}
+	vector := records[0].Sequence
+	cases := []struct {
+		enzymes []RestrictEnzyme
+		want    string
+		err     error
+	}{
+		// the backbone starts at the BamHI cut (G^GATCC) and ends at the EcoRI cut (G^AATTC)
+		{enzymes: []RestrictEnzyme{testEcoRI, testBamHI}, want: vector[87:] + vector[:61], err: nil},
+		// a single enzyme opens the vector at its cut
+		{enzymes: []RestrictEnzyme{testEcoRI}, want: vector[61:] + vector[:61], err: nil},
+		{enzymes: []RestrictEnzyme{testBamHI}, want: vector[87:] + vector[:87], err: nil},
+		{enzymes: []RestrictEnzyme{{Name: "BamHI", RecognitionSite: "GGATCC"}}, want: "", err: errors.New("invalid input: the cut positions of BamHI are unknown (use REBASE data or the default enzymes)")},
+		{enzymes: []RestrictEnzyme{{Name: "HindIII", RecognitionSite: "AAGCTT", TopCut: 1, BottomCut: 5, CutKnown: true}}, want: "", err: errors.New("invalid input: HindIII must cut the vector exactly once, found 0 site(s)")},
+		{enzymes: nil, want: "", err: errors.New("invalid input: a vector is linearized with one or two enzymes, not 0")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := LinearizeVector(vector, c.enzymes...)
+
+		// test similarity of expected and received value
+		if got != c.want {
+			t.Errorf("LinearizeVector(%v) == %v, want %v\n", c.enzymes, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("LinearizeVector(%v) == %v, want %v\n", c.enzymes, err, c.err)
+		}
+	}
+}
+
+func TestDesignGibson(t *testing.T) {
+	records, err := ParseFastaFromFile("tests/gibson1.fa")
+	if err != nil {
+		t.Fatalf("ParseFastaFromFile(tests/gibson1.fa) == %v\n", err)
+	}
+	backbone, err := LinearizeVector(records[0].Sequence, testEcoRI, testBamHI)
+	if err != nil {
+		t.Fatalf("LinearizeVector() == %v\n", err)
+	}
+	a, err := DesignGibson(FastaRecord{Name: "pVEC", Sequence: backbone}, records[1:], GibsonOptions{})
+	if err != nil {
+		t.Fatalf("DesignGibson() == %v\n", err)
+	}
+
+	// the overlaps with the vector are part of the insert primers, the overlap between the inserts is split
+	primers := []string{
+		"CACTAGTCAATATTCAACATTTGAAGATGTCTCTGACTTTAACTTGGCTACCTC",
+		"GCGAGCACTTGCACTACTCTATACGCGTTAGCTGA",
+		"AGAGTAGTGCAAGTGCTCGCAAAGGGGACA",
+		"GTGGGTACACACGGCGGATCTTAACGCTAGCCTTCCCCCCC",
+	}
+	overlaps := []string{"CACTAGTCAATATTCAACATTTGAAG", "AGAGTAGTGCAAGTGCTCGC", "GATCCGCCGTGTGTACCCAC"}
+	if len(a.Primers) != len(primers) {
+		t.Fatalf("DesignGibson() returned %d primers, want %d\n", len(a.Primers), len(primers))
+	}
+	for i, p := range a.Primers {
+		if p.Sequence != primers[i] {
+			t.Errorf("primer %s == %v, want %v\n", p.Name, p.Sequence, primers[i])
+		}
+		if p.Tm < AssemblyAnnealTm {
+			t.Errorf("primer %s has an annealing Tm of %v, want >= %v\n", p.Name, p.Tm, AssemblyAnnealTm)
+		}
+	}
+	for i, j := range a.Junctions {
+		if (j.Overlap != overlaps[i]) || !j.Unique || (j.Tm < GibsonOverlapTm) {
+			t.Errorf("junction %s/%s == %+v, want a unique overlap %v\n", j.Left, j.Right, j, overlaps[i])
+		}
+	}
+	if want := backbone + records[1].Sequence + records[2].Sequence; (a.Product != want) || (len(a.Warnings) != 0) {
+		t.Errorf("DesignGibson() == %v, %v, want %v without warnings\n", a.Product, a.Warnings, want)
+	}
+
+	// a repeated vector end cannot be avoided and is reported
+	repeat := backbone[len(backbone)-40:]
+	a, err = DesignGibson(FastaRecord{Name: "pVEC", Sequence: backbone[:50] + repeat + backbone[50:]}, records[1:2], GibsonOptions{})
+	if (err != nil) || (len(a.Warnings) != 1) || !strings.Contains(a.Warnings[0], "is not unique in the product") {
+		t.Errorf("DesignGibson() == %v, %v, want a warning about a repeated overlap\n", a.Warnings, err)
+	}
+
+	// a circular vector is linearized with the enzymes, whose sites are checked for methylation
+	xbaI := RestrictEnzyme{Name: "XbaI", RecognitionSite: "TCTAGA", TopCut: 1, BottomCut: 5, CutKnown: true, Dam: MethylationBlocked}
+	vector := records[0].Sequence[:100] + "TCTAGATC" + records[0].Sequence[100:]
+	a, err = DesignGibson(FastaRecord{Name: "pVEC", Sequence: vector}, records[1:], GibsonOptions{Enzymes: []RestrictEnzyme{testEcoRI, xbaI}})
+	want := []string{"vector: XbaI site at position 101 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain"}
+	if (err != nil) || !strings.HasPrefix(a.Product, vector[101:]) || !reflect.DeepEqual(a.Warnings, want) {
+		t.Errorf("DesignGibson() == %v, %q, %v, want a product that starts with %v and %q\n", a.Product, a.Warnings, err, vector[101:], want)
+	}
+
+	// invalid options and overlaps that cannot reach the Tm return errors
+	cases := []struct {
+		opts GibsonOptions
+		err  error
+	}{
+		{opts: GibsonOptions{MinOverlap: 30, MaxOverlap: 20}, err: errors.New("invalid input: overlaps of 30-20 nucleotides, expected a minimum >= 10 and a maximum >= the minimum")},
+		{opts: GibsonOptions{OverlapTm: 90}, err: errors.New("invalid input: no overlap of 20-40 nucleotides between pVEC and geneA reaches a Tm of 90.0°C")},
+	}
+	for _, c := range cases {
+		if _, err := DesignGibson(FastaRecord{Name: "pVEC", Sequence: backbone}, records[1:], c.opts); (err == nil) || (err.Error() != c.err.Error()) {
+			t.Errorf("DesignGibson(%+v) == %v, want %v\n", c.opts, err, c.err)
+		}
+	}
+}
+
+func TestHairpinStem(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+	}{
+		{in: "GAATTC", want: 1},
+		{in: "GGGGAAATTTCCCC", want: 5},
+		{in: "AAAAAAAAAA", want: 0},
+	}
+	for _, c := range cases {
+		if got := hairpinStem(c.in); got != c.want {
+			t.Errorf("hairpinStem(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FusionSiteLength gives the length of the overhangs (fusion sites) of a Golden Gate assembly
+	FusionSiteLength = 4
+
+	// GoldenGatePadding gives the default number of random nucleotides in front of the Type IIS site of a Golden Gate
+	// primer (enzymes cut inefficiently at the very end of a PCR product)
+	GoldenGatePadding = 4
+)
+
+// typeIISCleavageRegexp matches the *.re notation of enzymes that cut downstream of their site, e.g. "()(1/5)"
+var typeIISCleavageRegexp = regexp.MustCompile(`^\(\)\((-?\d+)/(-?\d+)\)$`)
+
+// GoldenGateOptions holds the parameters of `DesignGoldenGate'; zero values select the respective defaults
+type GoldenGateOptions struct {
+	Enzyme   RestrictEnzyme /* Type IIS enzyme that leaves 4-nt 5' overhangs, e.g. BsaI, BsmBI or BbsI */
+	Fusions  []string       /* one fusion site per junction (in front of every part and behind the last one); empty sites are chosen automatically */
+	Padding  int            /* number of random nucleotides in front of the Type IIS sites (defaults to `GoldenGatePadding') */
+	AnnealTm float64        /* Tm of the annealing parts of the primers in °C (defaults to `AssemblyAnnealTm') */
+}
+
+// GoldenGateAssembly holds the primers and the product of a Golden Gate assembly of one or more parts
+type GoldenGateAssembly struct {
+	Parts     []FastaRecord    /* the parts in the order of the product */
+	Fusions   []string         /* the fusion sites in front of every part and behind the last one (top strand) */
+	Positions []int            /* 1-based positions of the fusion sites in `Product' */
+	Primers   []AssemblyPrimer /* a forward and a reverse primer per part */
+	Product   string           /* sequence of the assembled parts between the first and the last fusion site (inclusive) */
+	Warnings  []string         /* problems that do not prevent the assembly */
+
+	partStarts []int /* 1-based positions of the parts in `Product' */
+}
+
+// Record returns the product of the assembly as a linear GenBank record named `name' with a feature per part and
+// fusion site
+func (a GoldenGateAssembly) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("Golden Gate assembly of %d parts", len(a.Parts)),
+		Molecule:   "DNA",
+		Topology:   "linear",
+		Division:   "SYN",
+		Sequence:   a.Product,
+	}
+	r.Features = append(r.Features, syntheticSource(len(a.Product)))
+	for i, f := range a.Fusions {
+		if i < len(a.Parts) {
+			r.Features = append(r.Features, NewFeature("misc_feature", a.partStarts[i], a.partStarts[i]+len(a.Parts[i].Sequence)-1, false, Qualifier{Key: "label", Value: a.Parts[i].Name}))
+		}
+		r.Features = append(r.Features, NewFeature("misc_feature", a.Positions[i], a.Positions[i]+len(f)-1, false, Qualifier{Key: "label", Value: "fusion site " + f}))
+	}
+	return r
+}
+
+// TypeIISCut returns the number of nucleotides between the recognition site of the Type IIS `enzyme' and its cut on
+// the top strand (the spacer) and the length of the 5' overhang that it leaves, e.g. 1 and 4 for BsaI (GGTCTC(1/5));
+// the cut positions are taken from REBASE data or from the *.re notation (e.g. "()(1/5)")
+func TypeIISCut(enzyme RestrictEnzyme) (int, int, error) {
+	n := len(enzyme.RecognitionSite)
+	if enzyme.CutKnown {
+		if (enzyme.TopCut >= n) && (enzyme.BottomCut > enzyme.TopCut) {
+			return enzyme.TopCut - n, enzyme.BottomCut - enzyme.TopCut, nil
+		}
+	} else if m := typeIISCleavageRegexp.FindStringSubmatch(enzyme.NoPalinCleav); m != nil {
+		top, _ := strconv.Atoi(m[1])
+		bottom, _ := strconv.Atoi(m[2])
+		if (top >= 0) && (bottom > top) {
+			return top, bottom - top, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid input: %s is not a Type IIS enzyme that cuts downstream of its site and leaves a 5' overhang", enzyme.Name)
+}
+
+// CheckFusionSites returns a description of every problem of a set of Golden Gate fusion `sites': every site must
+// consist of `FusionSiteLength' nucleotides, must not be palindromic (it would ligate to itself) and should contain a
+// G or C; for high-fidelity assemblies, every site must differ from every other site and its reverse complement in
+// at least two positions (sites that differ in a single position are ligated by mistake); no problems are returned
+// for a valid set
+func CheckFusionSites(sites []string) []string {
+	var problems []string
+	for i, s := range sites {
+		s = strings.ToUpper(s)
+		if p := fusionSiteProblem(s); p != "" {
+			problems = append(problems, p)
+			continue
+		}
+		for _, o := range sites[i+1:] {
+			if p := fusionSiteConflict(s, strings.ToUpper(o)); p != "" {
+				problems = append(problems, p)
+			}
+		}
+	}
+	return problems
+}
+
+// fusionSiteProblem returns a description of the problem of the fusion site `s' on its own, or an empty string
+func fusionSiteProblem(s string) string {
+	if len(s) != FusionSiteLength {
+		return fmt.Sprintf("fusion site %s must consist of %d nucleotides", s, FusionSiteLength)
+	}
+	for i := 0; i < len(s); i++ {
+		if !IsNucleotide(s[i]) {
+			return fmt.Sprintf("fusion site %s contains the invalid letter %s", s, string(s[i]))
+		}
+	}
+	switch {
+	case reverseComplementIUPAC(s) == s:
+		return fmt.Sprintf("fusion site %s is palindromic", s)
+	case !strings.ContainsAny(s, "GC"):
+		return fmt.Sprintf("fusion site %s contains no G or C (inefficient ligation)", s)
+	}
+	return ""
+}
+
+// fusionSiteConflict returns a description of the conflict between the fusion sites `s' and `o', or an empty string
+func fusionSiteConflict(s, o string) string {
+	for _, t := range []string{o, reverseComplementIUPAC(o)} {
+		mismatches := 0
+		for i := 0; (i < len(s)) && (i < len(t)); i++ {
+			if s[i] != t[i] {
+				mismatches++
+			}
+		}
+		switch {
+		case mismatches == 0:
+			return fmt.Sprintf("fusion site %s is not unique (it ligates to %s)", s, o)
+		case mismatches < 2:
+			return fmt.Sprintf("fusion site %s differs from %s (or its reverse complement) in a single position (low fidelity)", s, o)
+		}
+	}
+	return ""
+}
+
+// DesignGoldenGate designs primers for a Golden Gate assembly of `parts' in the given order with the Type IIS enzyme
+// `opts.Enzyme'; every primer consists of `opts.Padding' random nucleotides, the recognition site, the spacer of the
+// enzyme, the fusion site and the annealing part; fusion sites that are given in `opts.Fusions' are added between the
+// parts (or in front of the first and behind the last part), the other ones are chosen from the 4-nt windows at the
+// junction of two parts such that the assembly is scarless (at the ends of the assembly, the first and last 4
+// nucleotides of the parts are used); all fusion sites must pass `CheckFusionSites' and the parts must not contain
+// sites of the enzyme
+func DesignGoldenGate(parts []FastaRecord, opts GoldenGateOptions) (GoldenGateAssembly, error) {
+	spacer, overhang, err := TypeIISCut(opts.Enzyme)
+	if err != nil {
+		return GoldenGateAssembly{}, err
+	}
+	if overhang != FusionSiteLength {
+		return GoldenGateAssembly{}, fmt.Errorf("invalid input: %s leaves %d-nt overhangs, expected %d-nt overhangs", opts.Enzyme.Name, overhang, FusionSiteLength)
+	}
+	if opts.Padding == 0 {
+		opts.Padding = GoldenGatePadding
+	}
+	if opts.AnnealTm == 0 {
+		opts.AnnealTm = AssemblyAnnealTm
+	}
+	if len(parts) == 0 {
+		return GoldenGateAssembly{}, fmt.Errorf("invalid input: a Golden Gate assembly needs at least one part")
+	}
+	if (opts.Fusions != nil) && (len(opts.Fusions) != len(parts)+1) {
+		return GoldenGateAssembly{}, fmt.Errorf("invalid input: %d part(s) need %d fusion sites, not %d", len(parts), len(parts)+1, len(opts.Fusions))
+	}
+
+	// validate the parts and make sure that they do not contain sites of the enzyme
+	var a GoldenGateAssembly
+	site := strings.ToUpper(opts.Enzyme.RecognitionSite)
+	for i, p := range parts {
+		seq, err := ValidateSequence([]byte(p.Sequence))
+		if err != nil {
+			return GoldenGateAssembly{}, fmt.Errorf("invalid sequence of part %d: %v", i+1, err)
+		}
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("part%d", i+1)
+		}
+		if len(seq) < MinimumPrimerLength {
+			return GoldenGateAssembly{}, fmt.Errorf("invalid input: part %s has %d nucleotides, expected at least %d", p.Name, len(seq), MinimumPrimerLength)
+		}
+		if sites := FindSites(seq, site); len(sites) > 0 {
+			return GoldenGateAssembly{}, fmt.Errorf("invalid input: part %s has an internal %s site at position %d (remove it by a silent mutation)", p.Name, opts.Enzyme.Name, sites[0])
+		}
+		p.Sequence = seq
+		a.Parts = append(a.Parts, p)
+	}
+
+	// the given fusion sites are checked first, the others are chosen such that they do not conflict with them
+	n := len(a.Parts)
+	a.Fusions = make([]string, n+1)
+	var given []string
+	scar := make([]bool, n+1)    /* true for given fusion sites, which are added to the product */
+	upstream := make([]int, n+1) /* number of nucleotides of the chosen fusion sites from the upstream part */
+	for i := range a.Fusions {
+		if (opts.Fusions != nil) && (opts.Fusions[i] != "") {
+			a.Fusions[i], scar[i] = strings.ToUpper(opts.Fusions[i]), true
+			given = append(given, a.Fusions[i])
+		}
+	}
+	if problems := CheckFusionSites(given); len(problems) > 0 {
+		return GoldenGateAssembly{}, fmt.Errorf("invalid input: %s", strings.Join(problems, "; "))
+	}
+	tailF, tailR := make([]string, n), make([]string, n) /* tails (behind the spacer) of the forward and reverse primers */
+	for i := 0; i <= n; i++ {
+		if a.Fusions[i] != "" {
+			if i < n {
+				tailF[i] = a.Fusions[i]
+			}
+			if i > 0 {
+				tailR[i-1] = reverseComplementIUPAC(a.Fusions[i])
+			}
+			continue
+		}
+
+		// candidate windows (and the number of their nucleotides from the upstream part) at the junction
+		var candidates []string
+		var counts []int
+		switch i {
+		case 0:
+			candidates, counts = []string{a.Parts[0].Sequence[:FusionSiteLength]}, []int{0}
+		case n:
+			last := a.Parts[n-1].Sequence
+			candidates, counts = []string{last[len(last)-FusionSiteLength:]}, []int{FusionSiteLength}
+		default:
+			left, right := a.Parts[i-1].Sequence, a.Parts[i].Sequence
+			for _, c := range []int{2, 1, 3, 0, 4} { /* windows centered on the junction are preferred */
+				candidates = append(candidates, left[len(left)-c:]+right[:FusionSiteLength-c])
+				counts = append(counts, c)
+			}
+		}
+		chosen := -1
+		var problem string
+		for k, candidate := range candidates {
+			problem = fusionSiteProblem(candidate)
+			for _, f := range a.Fusions {
+				if (problem == "") && (f != "") {
+					problem = fusionSiteConflict(candidate, f)
+				}
+			}
+			if problem == "" {
+				chosen = k
+				break
+			}
+		}
+		if chosen < 0 {
+			return GoldenGateAssembly{}, fmt.Errorf("invalid input: no valid fusion site at junction %d (%s), specify one", i+1, problem)
+		}
+		a.Fusions[i], upstream[i] = candidates[chosen], counts[chosen]
+		if i < n {
+			tailF[i] = a.Fusions[i][:upstream[i]]
+		}
+		if i > 0 {
+			tailR[i-1] = reverseComplementIUPAC(a.Fusions[i][upstream[i]:])
+		}
+	}
+
+	// assemble the product and design a forward and a reverse primer per part
+	prefix := AddOverhang(site+strings.Repeat("A", spacer), opts.Padding, true)
+	a.Positions = make([]int, n+1)
+	for i, p := range a.Parts {
+		a.Positions[i] = len(a.Product) - upstream[i] + 1
+		if scar[i] {
+			a.Product += a.Fusions[i]
+		}
+		a.partStarts = append(a.partStarts, len(a.Product)+1)
+		a.Product += p.Sequence
+		primers, warnings := tailedPrimers(opts.AnnealTm, tailedPrimer{p.Name + "_F", p.Sequence, prefix + tailF[i]}, tailedPrimer{p.Name + "_R", reverseComplementIUPAC(p.Sequence), prefix + tailR[i]})
+		a.Primers = append(a.Primers, primers...)
+		a.Warnings = append(a.Warnings, warnings...)
+	}
+	a.Positions[n] = len(a.Product) - upstream[n] + 1
+	if scar[n] {
+		a.Product += a.Fusions[n]
+	}
+	if sites := FindSites(a.Product, site); len(sites) > 0 {
+		a.Warnings = append(a.Warnings, fmt.Sprintf("the junctions of the product create a %s site at position %d", opts.Enzyme.Name, sites[0]))
+	}
+	return a, nil
+}
@@ -0,0 +1,134 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var testBsaI = RestrictEnzyme{Name: "BsaI", RecognitionSite: "GGTCTC", NoPalinCleav: "()(1/5)"}
+
+func TestTypeIISCut(t *testing.T) {
+	cases := []struct {
+		in       RestrictEnzyme
+		spacer   int
+		overhang int
+		err      error
+	}{
+		{in: testBsaI, spacer: 1, overhang: 4, err: nil},
+		{in: RestrictEnzyme{Name: "BsaI", RecognitionSite: "GGTCTC", TopCut: 7, BottomCut: 11, CutKnown: true}, spacer: 1, overhang: 4, err: nil},
+		{in: RestrictEnzyme{Name: "BbsI", RecognitionSite: "GAAGAC", NoPalinCleav: "()(2/6)"}, spacer: 2, overhang: 4, err: nil},
+		{in: RestrictEnzyme{Name: "SapI", RecognitionSite: "GCTCTTC", NoPalinCleav: "()(1/4)"}, spacer: 1, overhang: 3, err: nil},
+		{in: testEcoRI, spacer: 0, overhang: 0, err: errors.New("invalid input: EcoRI is not a Type IIS enzyme that cuts downstream of its site and leaves a 5' overhang")},
+		{in: RestrictEnzyme{Name: "BsmI", RecognitionSite: "GAATGC", NoPalinCleav: "()(1/-1)"}, spacer: 0, overhang: 0, err: errors.New("invalid input: BsmI is not a Type IIS enzyme that cuts downstream of its site and leaves a 5' overhang")},
+	}
+	for _, c := range cases {
+		spacer, overhang, err := TypeIISCut(c.in)
+		if (spacer != c.spacer) || (overhang != c.overhang) {
+			t.Errorf("TypeIISCut(%v) == %v, %v, want %v, %v\n", c.in.Name, spacer, overhang, c.spacer, c.overhang)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("TypeIISCut(%v) == %v, want %v\n", c.in.Name, err, c.err)
+		}
+	}
+}
+
+func TestCheckFusionSites(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want []string
+	}{
+		// a standard set of MoClo fusion sites
+		{in: []string{"GGAG", "AATG", "GCTT", "CGCT"}, want: nil},
+		{in: []string{"GAATTC"}, want: []string{"fusion site GAATTC must consist of 4 nucleotides"}},
+		{in: []string{"AATT", "ATAT", "GGAG"}, want: []string{"fusion site AATT is palindromic", "fusion site ATAT is palindromic"}},
+		{in: []string{"aaat"}, want: []string{"fusion site AAAT contains no G or C (inefficient ligation)"}},
+		{in: []string{"GGAG", "CTCC"}, want: []string{"fusion site GGAG is not unique (it ligates to CTCC)"}},
+		{in: []string{"GGAG", "GGTG"}, want: []string{"fusion site GGAG differs from GGTG (or its reverse complement) in a single position (low fidelity)"}},
+	}
+	for _, c := range cases {
+		if got := CheckFusionSites(c.in); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("CheckFusionSites(%v) == %q, want %q\n", c.in, got, c.want)
+		}
+	}
+}
+
+func TestDesignGoldenGate(t *testing.T) {
+	parts, err := ParseFastaFromFile("tests/goldengate1.fa")
+	if err != nil {
+		t.Fatalf("ParseFastaFromFile(tests/goldengate1.fa) == %v\n", err)
+	}
+	cases := []struct {
+		fusions []string
+		want    []string /* fusion sites */
+		pos     []int    /* positions of the fusion sites */
+		primers []string
+		scars   bool /* true if the given fusion sites are added to the product */
+	}{
+		// scarless assembly with automatically chosen fusion sites
+		{
+			fusions: nil,
+			want:    []string{"AAGC", "CAAT", "AAAG", "GTTA"},
+			pos:     []int{1, 49, 109, 152},
+			primers: []string{
+				"AGCTGGTCTCAAAGCCCAATAAACCACTCTGACTGG", "AGCTGGTCTCAATTGTCGTTGCCTATATCCCTATTCGGC",
+				"AGCTGGTCTCACAATGTGTGCGGCGACCCT", "AGCTGGTCTCACTTTATCAAATAGGTTTAGGCAACGGCGA",
+				"AGCTGGTCTCAAAAGGAGTCTAGCAGCCGCAGT", "AGCTGGTCTCATAACACGGACGAGGTATTGTGCC",
+			},
+		},
+		// given fusion sites are added, the automatically chosen ones must not conflict with them (AAAG ~ AATG)
+		{
+			fusions: []string{"GGAG", "AATG", "", "CGCT"},
+			want:    []string{"GGAG", "AATG", "AGGA", "CGCT"},
+			pos:     []int{1, 55, 119, 164},
+			primers: []string{
+				"AGCTGGTCTCAGGAGAAGCCCAATAAACCACTCTGACTGG", "AGCTGGTCTCACATTTGTCGTTGCCTATATCCCTATTCGGC",
+				"AGCTGGTCTCAAATGATGTGTGCGGCGACCCT", "AGCTGGTCTCATCCTTTATCAAATAGGTTTAGGCAACGGCGA",
+				"AGCTGGTCTCAAGGAGTCTAGCAGCCGCAGT", "AGCTGGTCTCAAGCGTAACACGGACGAGGTATTGTGCC",
+			},
+			scars: true,
+		},
+	}
+	for _, c := range cases {
+		a, err := DesignGoldenGate(parts, GoldenGateOptions{Enzyme: testBsaI, Fusions: c.fusions})
+		if err != nil {
+			t.Fatalf("DesignGoldenGate(%v) == %v\n", c.fusions, err)
+		}
+		var primers []string
+		for _, p := range a.Primers {
+			primers = append(primers, p.Sequence)
+		}
+		if !reflect.DeepEqual(a.Fusions, c.want) || !reflect.DeepEqual(a.Positions, c.pos) || !reflect.DeepEqual(primers, c.primers) || (len(a.Warnings) != 0) {
+			t.Errorf("DesignGoldenGate(%v) == %v, %v, %v, %v, want %v, %v, %v\n", c.fusions, a.Fusions, a.Positions, primers, a.Warnings, c.want, c.pos, c.primers)
+		}
+		for i, f := range a.Fusions {
+			if a.Product[a.Positions[i]-1:a.Positions[i]+len(f)-1] != f {
+				t.Errorf("DesignGoldenGate(%v) places fusion site %v at position %v of %v\n", c.fusions, f, a.Positions[i], a.Product)
+			}
+		}
+		want := parts[0].Sequence + parts[1].Sequence + parts[2].Sequence
+		if c.scars {
+			want = "GGAG" + parts[0].Sequence + "AATG" + parts[1].Sequence + parts[2].Sequence + "CGCT"
+		}
+		if a.Product != want {
+			t.Errorf("DesignGoldenGate(%v) == %v, want %v\n", c.fusions, a.Product, want)
+		}
+	}
+
+	// invalid enzymes, fusion sites and parts with internal sites return errors
+	errCases := []struct {
+		parts []FastaRecord
+		opts  GoldenGateOptions
+		err   error
+	}{
+		{parts: parts, opts: GoldenGateOptions{Enzyme: RestrictEnzyme{Name: "SapI", RecognitionSite: "GCTCTTC", NoPalinCleav: "()(1/4)"}}, err: errors.New("invalid input: SapI leaves 3-nt overhangs, expected 4-nt overhangs")},
+		{parts: parts, opts: GoldenGateOptions{Enzyme: testBsaI, Fusions: []string{"GGAG", "CGCT"}}, err: errors.New("invalid input: 3 part(s) need 4 fusion sites, not 2")},
+		{parts: parts, opts: GoldenGateOptions{Enzyme: testBsaI, Fusions: []string{"GGAG", "GATC", "", ""}}, err: errors.New("invalid input: fusion site GATC is palindromic")},
+		{parts: []FastaRecord{{Name: "p", Sequence: "ATGGCCGAGACCTTTAAA"}}, opts: GoldenGateOptions{Enzyme: testBsaI}, err: errors.New("invalid input: part p has an internal BsaI site at position 7 (remove it by a silent mutation)")},
+	}
+	for _, c := range errCases {
+		if _, err := DesignGoldenGate(c.parts, c.opts); (err == nil) || (err.Error() != c.err.Error()) {
+			t.Errorf("DesignGoldenGate(%+v) == %v, want %v\n", c.opts, err, c.err)
+		}
+	}
+}
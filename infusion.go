@@ -0,0 +1,131 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// InFusionHomology gives the length of the homology arms that In-Fusion primers add to the inserts
+	InFusionHomology = 15
+
+	// SLICHomology gives the default length of the homology arms of SLIC (sequence and ligation independent cloning)
+	// primers, which need longer homologies than In-Fusion
+	SLICHomology = 25
+)
+
+// InFusionOptions holds the parameters of `DesignInFusion'; the vector is linearized either by the `Enzymes' or by
+// inverse PCR (if `InsertAt' is set)
+type InFusionOptions struct {
+	Enzymes  []RestrictEnzyme /* one or two enzymes with known cut positions that linearize the vector */
+	InsertAt int              /* 1-based position of the vector behind which the inserts are placed (inverse PCR) */
+	Delete   int              /* number of vector nucleotides behind `InsertAt' that are deleted by the inverse PCR */
+	Homology int              /* length of the homology arms (defaults to `InFusionHomology') */
+	AnnealTm float64          /* Tm of the annealing parts of the primers in °C (defaults to `AssemblyAnnealTm') */
+}
+
+// LinearizeVectorInFusion returns the backbone of the circular `vector' after a digest with one or two restriction
+// `enzymes' like `LinearizeVector', but with the ends that In-Fusion and SLIC homology arms must match: a 5' overhang
+// is part of the homology (so it is found at both ends of the backbone), a 3' overhang is not (it is removed during the
+// reaction), i.e. the backbone starts at the top strand cut of the last enzyme and ends at the bottom strand cut of the
+// first enzyme; the cut positions of the enzymes must be known (e.g. from REBASE data)
+func LinearizeVectorInFusion(vector string, enzymes ...RestrictEnzyme) (string, error) {
+	if (len(enzymes) < 1) || (len(enzymes) > 2) {
+		return "", fmt.Errorf("invalid input: a vector is linearized with one or two enzymes, not %d", len(enzymes))
+	}
+	seq, err := ValidateSequence([]byte(vector))
+	if err != nil {
+		return "", fmt.Errorf("invalid vector sequence: %v", err)
+	}
+	var tops, bottoms []int
+	for _, e := range enzymes {
+		if !e.CutKnown {
+			return "", fmt.Errorf("invalid input: the cut positions of %s are unknown (use REBASE data or linearize the vector by inverse PCR)", e.Name)
+		}
+		_, top, bottom, err := vectorCut(seq, e)
+		if err != nil {
+			return "", err
+		}
+		tops, bottoms = append(tops, top), append(bottoms, bottom)
+	}
+	start, end := tops[len(tops)-1], bottoms[0]
+	length := len(seq) + end - start /* a single enzyme: the full vector plus a 5' or minus a 3' overhang */
+	if len(enzymes) == 2 {
+		length = ((end-start)%len(seq) + len(seq)) % len(seq)
+		if length == 0 {
+			return "", fmt.Errorf("invalid input: %s and %s cut the vector at the same position", enzymes[0].Name, enzymes[1].Name)
+		}
+	}
+	start = ((start % len(seq)) + len(seq)) % len(seq)
+	return strings.Repeat(seq, 3)[start : start+length], nil
+}
+
+// DesignInFusion designs primers for an In-Fusion or SLIC assembly of the `vector' and one or more `inserts' that are
+// placed in the given order between the ends of the linearized vector; every junction gets a homology arm of
+// `opts.Homology' nucleotides that is added to the forward primer of the downstream insert (the junction of the last
+// insert and the vector is added to its reverse primer); if the vector is linearized by inverse PCR, primers without
+// tails that amplify the vector backbone are added as well, otherwise sites of the enzymes that overlap Dam or Dcm
+// methylation of the vector are reported in the warnings
+func DesignInFusion(vector FastaRecord, inserts []FastaRecord, opts InFusionOptions) (Assembly, error) {
+	if opts.Homology == 0 {
+		opts.Homology = InFusionHomology
+	}
+	if opts.AnnealTm == 0 {
+		opts.AnnealTm = AssemblyAnnealTm
+	}
+	if opts.Homology < MinimumPrimerLength {
+		return Assembly{}, fmt.Errorf("invalid input: homology arms of %d nucleotides, expected at least %d", opts.Homology, MinimumPrimerLength)
+	}
+	if (len(opts.Enzymes) > 0) == (opts.InsertAt > 0) {
+		return Assembly{}, fmt.Errorf("invalid input: linearize the vector either with enzymes or by inverse PCR")
+	}
+
+	// linearize the vector
+	seq, err := ValidateSequence([]byte(vector.Sequence))
+	if err != nil {
+		return Assembly{}, fmt.Errorf("invalid vector sequence: %v", err)
+	}
+	if opts.InsertAt > 0 {
+		if (opts.InsertAt > len(seq)) || (opts.Delete < 0) || (opts.Delete > len(seq)-2*MinimumPrimerLength) {
+			return Assembly{}, fmt.Errorf("invalid input: insert position %d and deletion of %d nucleotides, expected a position between 1 and %d and a deletion that leaves at least %d nucleotides", opts.InsertAt, opts.Delete, len(seq), 2*MinimumPrimerLength)
+		}
+		start := (opts.InsertAt + opts.Delete) % len(seq)
+		vector.Sequence = (seq + seq)[start : start+len(seq)-opts.Delete]
+	} else if vector.Sequence, err = LinearizeVectorInFusion(seq, opts.Enzymes...); err != nil {
+		return Assembly{}, err
+	}
+	a, err := newAssembly(vector, inserts)
+	if err != nil {
+		return Assembly{}, err
+	}
+	a.Warnings = methylationWarnings("vector", seq, opts.Enzymes...)
+
+	// add the homology arms of all junctions (fragment k and its successor) to the insert primers
+	n := len(a.Fragments)
+	tailF, tailR := make([]string, n), make([]string, n) /* tails of the forward and reverse primer of every fragment */
+	for k := 0; k < n; k++ {
+		left, right := a.Fragments[k], a.Fragments[(k+1)%n]
+		if (len(left.Sequence) < opts.Homology) || (len(right.Sequence) < opts.Homology) {
+			return Assembly{}, fmt.Errorf("invalid input: %s and %s must have at least %d nucleotides for the homology arm", left.Name, right.Name, opts.Homology)
+		}
+		if k == n-1 {
+			j := a.junction(left.Name, right.Name, right.Sequence[:opts.Homology])
+			a.addJunction(j)
+			tailR[k] = reverseComplementIUPAC(j.Overlap)
+			continue
+		}
+		j := a.junction(left.Name, right.Name, left.Sequence[len(left.Sequence)-opts.Homology:])
+		a.addJunction(j)
+		tailF[k+1] = j.Overlap
+	}
+	a.addPrimers(tailF, tailR, opts.AnnealTm)
+
+	// the backbone of an inverse PCR is amplified without tails
+	if opts.InsertAt > 0 {
+		backbone := a.Fragments[0]
+		primers, warnings := tailedPrimers(opts.AnnealTm, tailedPrimer{name: backbone.Name + "_F", template: backbone.Sequence}, tailedPrimer{name: backbone.Name + "_R", template: reverseComplementIUPAC(backbone.Sequence)})
+		a.Primers = append(a.Primers, primers...)
+		a.Warnings = append(a.Warnings, warnings...)
+	}
+	return a, nil
+}
@@ -0,0 +1,110 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testKpnI leaves a 3' overhang (GGTAC^C)
+var testKpnI = RestrictEnzyme{Name: "KpnI", RecognitionSite: "GGTACC", TopCut: 5, BottomCut: 1, CutKnown: true}
+
+func TestLinearizeVectorInFusion(t *testing.T) {
+	vector := "TTTTGAATTCAAAACCCCGGTACCGGGG"
+	cases := []struct {
+		enzymes []RestrictEnzyme
+		want    string
+		err     error
+	}{
+		// the 5' overhang of EcoRI is found at both ends
+		{enzymes: []RestrictEnzyme{testEcoRI}, want: "AATTCAAAACCCCGGTACCGGGG" + "TTTTGAATT", err: nil},
+		// the 3' overhang of KpnI is removed
+		{enzymes: []RestrictEnzyme{testKpnI}, want: "CGGGG" + "TTTTGAATTCAAAACCCCG", err: nil},
+		{enzymes: []RestrictEnzyme{testEcoRI, testKpnI}, want: "CGGGG" + "TTTTGAATT", err: nil},
+		{enzymes: []RestrictEnzyme{testKpnI, testEcoRI}, want: "AATTCAAAACCCCG", err: nil},
+		{enzymes: []RestrictEnzyme{{Name: "BamHI", RecognitionSite: "GGATCC"}}, want: "", err: errors.New("invalid input: the cut positions of BamHI are unknown (use REBASE data or linearize the vector by inverse PCR)")},
+		{enzymes: nil, want: "", err: errors.New("invalid input: a vector is linearized with one or two enzymes, not 0")},
+	}
+	for _, c := range cases {
+		got, err := LinearizeVectorInFusion(vector, c.enzymes...)
+		if got != c.want {
+			t.Errorf("LinearizeVectorInFusion(%v) == %v, want %v\n", c.enzymes, got, c.want)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("LinearizeVectorInFusion(%v) == %v, want %v\n", c.enzymes, err, c.err)
+		}
+	}
+}
+
+func TestLinearizeVectorInFusionDefaultEnzymes(t *testing.T) {
+	// the default enzymes have the cut positions that In-Fusion needs
+	enzymes, err := DefaultEnzymes()
+	if err != nil {
+		t.Fatalf("DefaultEnzymes() == %v, want <nil>\n", err)
+	}
+	vector := "TTTTGAATTCAAAACCCCGGTACCGGGGGGATCCAA"
+	cases := []struct {
+		enzymes []string
+		want    string
+	}{
+		{enzymes: []string{"EcoRI", "KpnI"}, want: "CGGGGGGATCCAA" + "TTTTGAATT"},
+		{enzymes: []string{"BamHI"}, want: "GATCCAA" + "TTTTGAATTCAAAACCCCGGTACCGGGGGGATC"},
+		{enzymes: []string{"Acc65I", "BamHI"}, want: "GATCCAATTTTGAATTCAAAACCCCGGTAC"},
+	}
+	for _, c := range cases {
+		var list []RestrictEnzyme
+		for _, name := range c.enzymes {
+			list = append(list, enzymes[name])
+		}
+		got, err := LinearizeVectorInFusion(vector, list...)
+		if (err != nil) || (got != c.want) {
+			t.Errorf("LinearizeVectorInFusion(%v) == %v, %v, want %v, <nil>\n", c.enzymes, got, err, c.want)
+		}
+	}
+}
+
+func TestDesignInFusion(t *testing.T) {
+	records, err := ParseFastaFromFile("tests/gibson1.fa")
+	if err != nil {
+		t.Fatalf("ParseFastaFromFile(tests/gibson1.fa) == %v\n", err)
+	}
+	cases := []struct {
+		opts     InFusionOptions
+		primers  []string
+		overlaps []string
+		err      error
+	}{
+		// the homology arms include the 5' overhangs of EcoRI and BamHI
+		{
+			opts:     InFusionOptions{Enzymes: []RestrictEnzyme{testEcoRI, testBamHI}},
+			primers:  []string{"AACATTTGAAGAATTATGTCTCTGACTTTAACTTGGCTACCTC", "GCACTACTCTATACGCGTTAGCTGA", "CGTATAGAGTAGTGCAAGTGCTCGCAAAGGGGACA", "TACACACGGCGGATCTTAACGCTAGCCTTCCCCCCC"},
+			overlaps: []string{"AACATTTGAAGAATT", "CGTATAGAGTAGTGC", "GATCCGCCGTGTGTA"},
+			err:      nil,
+		},
+		// SLIC with a vector that is linearized by inverse PCR behind position 60
+		{
+			opts:     InFusionOptions{InsertAt: 60, Homology: SLICHomology},
+			primers:  []string{"CACTAGTCAATATTCAACATTTGAAATGTCTCTGACTTTAACTTGGCTACCTC", "GCACTACTCTATACGCGTTAGCTGA", "TCAGCTAACGCGTATAGAGTAGTGCAAGTGCTCGCAAAGGGGACA", "AAACAGGTATAGTTTGGCAGAATTCTTAACGCTAGCCTTCCCCCCC", "GAATTCTGCCAAACTATACCTGTTTAGGAT", "TTCAAATGTTGAATATTGACTAGTGCCTGG"},
+			overlaps: []string{"CACTAGTCAATATTCAACATTTGAA", "TCAGCTAACGCGTATAGAGTAGTGC", "GAATTCTGCCAAACTATACCTGTTT"},
+			err:      nil,
+		},
+		{opts: InFusionOptions{}, err: errors.New("invalid input: linearize the vector either with enzymes or by inverse PCR")},
+		{opts: InFusionOptions{InsertAt: 60, Homology: 8}, err: errors.New("invalid input: homology arms of 8 nucleotides, expected at least 10")},
+	}
+	for _, c := range cases {
+		a, err := DesignInFusion(records[0], records[1:], c.opts)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("DesignInFusion(%+v) == %v, want %v\n", c.opts, err, c.err)
+		}
+		var primers, overlaps []string
+		for _, p := range a.Primers {
+			primers = append(primers, p.Sequence)
+		}
+		for _, j := range a.Junctions {
+			overlaps = append(overlaps, j.Overlap)
+		}
+		if !reflect.DeepEqual(primers, c.primers) || !reflect.DeepEqual(overlaps, c.overlaps) {
+			t.Errorf("DesignInFusion(%+v) == %v, %v, want %v, %v\n", c.opts, primers, overlaps, c.primers, c.overlaps)
+		}
+	}
+}
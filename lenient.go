@@ -0,0 +1,132 @@
+package cloningprimer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// sequenceKeywords holds the first words of the GenBank header lines (e.g. of NCBI's web view) that are skipped by
+// the lenient sequence parser
+var sequenceKeywords = map[string]bool{
+	"LOCUS": true, "DEFINITION": true, "ACCESSION": true, "VERSION": true, "DBLINK": true, "KEYWORDS": true, "SOURCE": true,
+	"ORGANISM": true, "REFERENCE": true, "AUTHORS": true, "CONSRTM": true, "TITLE": true, "JOURNAL": true, "PUBMED": true,
+	"REMARK": true, "COMMENT": true, "FEATURES": true, "BASE": true, "CONTIG": true, "ORIGIN": true,
+}
+
+// emblLineCodes holds the two letter codes of EMBL lines that are skipped by the lenient sequence parser
+var emblLineCodes = map[string]bool{
+	"ID": true, "AC": true, "PR": true, "DT": true, "DE": true, "KW": true, "OS": true, "OC": true, "OG": true, "RN": true,
+	"RC": true, "RP": true, "RX": true, "RG": true, "RA": true, "RT": true, "RL": true, "DR": true, "CC": true, "AH": true,
+	"AS": true, "FH": true, "FT": true, "CO": true, "SQ": true, "XX": true,
+}
+
+// ParseSequenceLenient parses a nucleotide sequence from text that was copied from web pages, sequence files or word
+// processors; position numbers, white space, punctuation and other formatting characters are ignored, just like
+// header lines (e.g. a FASTA header or the header and feature table of a GenBank or EMBL record including indented
+// continuation lines) and comment lines; parsing stops at the end of the first record ("//" or a second FASTA header);
+// other letters than A,T,C,G return the sequence up to the letter and a `*ParseError' with the line and column of the
+// letter in the original text; the case of the letters is preserved
+func ParseSequenceLenient(r io.Reader) (string, error) {
+	b, err := readData(r)
+	if err != nil {
+		return "", err
+	}
+	return parseSequenceLenient(b, "input")
+}
+
+// ParseSequenceLenientFromFile parses a nucleotide sequence from a (possibly gzip-compressed) text file of any format
+// (see `ParseSequenceLenient')
+func ParseSequenceLenientFromFile(file string) (string, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return "", err
+	}
+	return parseSequenceLenient(b, file)
+}
+
+// ValidateSequenceLenient is the lenient equivalent of `ValidateSequence': it parses `seq' like `ParseSequenceLenient'
+// and returns the upper case sequence
+func ValidateSequenceLenient(seq []byte) (string, error) {
+	s, err := parseSequenceLenient(seq, "input")
+	return strings.ToUpper(s), err
+}
+
+// parseSequenceLenient parses the contents `b' of a text file leniently; `source' identifies the data in errors
+func parseSequenceLenient(b []byte, source string) (string, error) {
+	var seq []byte       /* the growing nucleotide sequence */
+	var line int         /* variable to keep track of the current line (for error messages) */
+	var comment bool     /* variable to keep track of whether the current line is part of a C-style comment */
+	var fastaHeader bool /* true if a FASTA header was parsed */
+	var record bool      /* true between "LOCUS"/"ID" or "FEATURES" and "ORIGIN"/"SQ" (header and feature table of a flat file) */
+	var section bool     /* true after a header line (its indented continuation lines are skipped) */
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+Loop:
+	for scanner.Scan() {
+		line++
+		raw := scanner.Text()
+		text := strings.TrimFunc(raw, func(r rune) bool { return unicode.IsSpace(r) || unicode.Is(unicode.Cf, r) })
+		fields := strings.Fields(text)
+
+		// skip empty lines, comments and headers
+		continuation := section && (text != "") && unicode.IsSpace(rune(raw[0])) && !unicode.IsDigit(rune(text[0]))
+		section = false
+		switch {
+		case comment || strings.HasPrefix(text, "/*"):
+			comment = !strings.Contains(text, "*/")
+			continue
+		case text == "":
+			continue
+		case strings.HasPrefix(text, "//"):
+			break Loop
+		case strings.HasPrefix(text, ">"):
+			if fastaHeader || (len(seq) > 0) {
+				break Loop
+			}
+			fastaHeader = true
+			continue
+		case record:
+			record = (fields[0] != "ORIGIN") && (fields[0] != "SQ")
+			continue
+		case (fields[0] == "LOCUS") || (fields[0] == "FEATURES"):
+			record = true
+			continue
+		case emblLineCodes[fields[0]] && ((text == fields[0]) || strings.HasPrefix(text, fields[0]+"   ")):
+			record = (fields[0] == "ID") || (fields[0] == "FH") || (fields[0] == "FT")
+			continue
+		case strings.HasPrefix(text, ";"):
+			continue
+		case continuation || sequenceKeywords[fields[0]]:
+			section = fields[0] != "ORIGIN"
+			continue
+		}
+
+		// parse the nucleotides of the line and ignore all formatting characters
+		column := 0
+		for _, r := range raw {
+			column++
+			switch {
+			case (r <= unicode.MaxASCII) && IsNucleotide(byte(r)):
+				seq = append(seq, byte(r))
+			case !isFormatting(r):
+				return string(seq), &ParseError{Source: source, Line: line, Column: column, Err: &NucleotideError{Letter: r, Position: len(seq) + 1}}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return string(seq), fmt.Errorf("error reading sequence data: %v", err)
+	}
+	logf("parsed %d nucleotides from '%s'\n", len(seq), source)
+	return string(seq), nil
+}
+
+// isFormatting returns true for characters that are not part of a sequence, i.e. digits, white space, punctuation,
+// symbols and control or format characters (e.g. zero-width spaces in texts that were copied from word processors)
+func isFormatting(r rune) bool {
+	return unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsControl(r) || unicode.Is(unicode.Cf, r)
+}
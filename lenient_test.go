@@ -0,0 +1,78 @@
+package cloningprimer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testCaseLenient struct {
+	in   string
+	want string
+	err  error
+}
+
+func TestParseSequenceLenientFromFile(t *testing.T) {
+	cases := []testCaseLenient{
+		// NCBI web view with header lines, position numbers and a non-breaking space
+		{in: "tests/lenient1.txt", want: "gatgggattggggttttcccctcccatgtgctcaagac", err: nil},
+		// FASTA text from a word processor with a byte order mark, zero-width spaces, dashes and quotes
+		{in: "tests/lenient2.txt", want: "ATGGCCGCGTAA", err: nil},
+		// complete NCBI record with continuation lines and a feature table
+		{in: "tests/lenient4.txt", want: "gatgggattggggttttcccctcccatgtgctcaagac", err: nil},
+		// unknown header lines are not skipped
+		{in: "tests/lenient3.txt", want: "", err: errors.New("tests/lenient3.txt:1:1: invalid input S at position 1, expected sequence of lower or upper case A,T,C,G")},
+		{in: "tests/nonexisting.txt", want: "", err: errors.New("error opening file: open tests/nonexisting.txt: no such file or directory")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseSequenceLenientFromFile(c.in)
+
+		// test similarity of expected and received value
+		if got != c.want {
+			t.Errorf("ParseSequenceLenientFromFile(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseSequenceLenientFromFile(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
+
+func TestParseSequenceLenient(t *testing.T) {
+	cases := []testCaseLenient{
+		// C-style comments and EMBL line codes are skipped
+		{in: "/* a comment\nATGXXX */\nID   x; SV 1; linear; DNA; STD; SYN; 6 BP.\nXX\nSQ   Sequence 6 BP;\n     atggcc 6\n", want: "atggcc", err: nil},
+		// continuation lines of header lines and EMBL feature tables are skipped
+		{in: "DEFINITION  Homo sapiens tumor protein p53 (TP53),\n            transcript variant 1, mRNA.\n        1 atggcc\n", want: "atggcc", err: nil},
+		{in: "FH   Key             Location/Qualifiers\nFT   gene            1..6\nFT                   /gene=\"TP53\"\nSQ   Sequence 6 BP;\n     atggcc 6\n", want: "atggcc", err: nil},
+		// parsing stops at the end of the first record
+		{in: ">seq1\nATG GCC\n>seq2\nTTT\n", want: "ATGGCC", err: nil},
+		{in: "ATG\n//\nTTT\n", want: "ATG", err: nil},
+		// invalid letters are reported with their line and column
+		{in: "1 ATG\n4 GCX\n", want: "ATGGC", err: errors.New("input:2:5: invalid input X at position 6, expected sequence of lower or upper case A,T,C,G")},
+		{in: "ATGäC", want: "ATG", err: errors.New("input:1:4: invalid input ä at position 4, expected sequence of lower or upper case A,T,C,G")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseSequenceLenient(strings.NewReader(c.in))
+
+		// test similarity of expected and received value
+		if got != c.want {
+			t.Errorf("ParseSequenceLenient(%q) == %v, want %v\n", c.in, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseSequenceLenient(%q) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+
+	// `ValidateSequenceLenient' returns upper case sequences
+	if got, err := ValidateSequenceLenient([]byte("  1 atgg ccgc\n 9 gt\n")); (got != "ATGGCCGCGT") || (err != nil) {
+		t.Errorf("ValidateSequenceLenient() == %v, %v, want ATGGCCGCGT, <nil>\n", got, err)
+	}
+}
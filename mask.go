@@ -0,0 +1,145 @@
+package cloningprimer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaskedEndLength gives the number of nucleotides at the 3' end of an annealing region that should not be soft-masked
+// (i.e. lower case, e.g. repeats in genome FASTA files of Ensembl or UCSC)
+const MaskedEndLength = 5
+
+// MaskReport describes how the annealing region of a primer that was computed by `FindForwardMasked' or
+// `FindReverseMasked' overlaps soft-masked nucleotides
+type MaskReport struct {
+	Length    int  /* number of complementary nucleotides of the primer (may differ from the requested length) */
+	Masked    int  /* number of soft-masked nucleotides in the annealing region */
+	MaskedEnd bool /* true if the last `MaskedEndLength' nucleotides of the annealing region contain masked nucleotides */
+}
+
+// Fallback returns true if no annealing region without soft-masked nucleotides was found and the primer had to be
+// placed on masked sequence
+func (m MaskReport) Fallback() bool {
+	return m.Masked > 0
+}
+
+// Message returns a note about the soft-masked nucleotides of the `primer' (e.g. "forward") primer, or an empty string
+// if its annealing region is not masked
+func (m MaskReport) Message(primer string) string {
+	switch {
+	case !m.Fallback():
+		return ""
+	case m.MaskedEnd:
+		return fmt.Sprintf("the %s primer had to be placed on soft-masked sequence: %d of its %d complementary nucleotides are masked, including its 3' end", primer, m.Masked, m.Length)
+	default:
+		return fmt.Sprintf("the %s primer had to be placed on soft-masked sequence: %d of its %d complementary nucleotides are masked, its 3' end (%d nucleotides) is not masked", primer, m.Masked, m.Length, MaskedEndLength)
+	}
+}
+
+// IsMasked returns true if `l' is a soft-masked (lower case) nucleotide
+func IsMasked(l byte) bool {
+	return (l == 'a') || (l == 't') || (l == 'c') || (l == 'g')
+}
+
+// ValidateSequenceKeepCase is the equivalent of `ValidateSequence' that preserves the case of the nucleotides in
+// `seq', i.e. the information about soft-masked regions
+func ValidateSequenceKeepCase(seq []byte) (string, error) {
+	if seq == nil {
+		return "", errors.New("nil slice is not a valid sequence")
+	}
+	var s []byte
+	for _, b := range seq {
+		if b == 9 || b == 10 || b == 11 || b == 12 || b == 13 || b == 32 {
+			continue
+		}
+		if !IsNucleotide(b) {
+			s = append(s, b)
+			s = append(s, []byte(" ... this character is not a valid nucleotide (must be one of A,T,C,G)")...)
+			return string(s), fmt.Errorf("invalid char in nucleotide sequence: %v", string(b))
+		}
+		s = append(s, b)
+	}
+	return string(s), nil
+}
+
+// FindForwardMasked is the equivalent of `FindForward' for soft-masked sequences: if the annealing region of
+// `length' nucleotides overlaps lower case nucleotides of `seq', the complementary length closest to `length' (between
+// `MinimumPrimerLength' and `MaximumPrimerLength') is used whose annealing region is not masked at all or, failing
+// that, whose last `MaskedEndLength' nucleotides are not masked; if every length overlaps masked nucleotides at the 3'
+// end, `length' is used; the returned report states the chosen length and whether the primer binds masked sequence
+func FindForwardMasked(seq, restrict string, seqStart, length, random int, startCodon bool) (string, MaskReport, error) {
+	report := MaskReport{Length: length}
+	if (seqStart >= 1) && (seqStart+length-1 <= len(seq)) && (length >= MinimumPrimerLength) && (length <= MaximumPrimerLength) {
+		report = chooseMaskedLength(length, len(seq)-seqStart+1, func(l int) string {
+			return seq[seqStart-1 : seqStart-1+l]
+		})
+	}
+	primer, err := FindForward(seq, restrict, seqStart, report.Length, random, startCodon)
+	if err != nil {
+		return "", MaskReport{}, err
+	}
+	return primer, report, nil
+}
+
+// FindReverseMasked is the equivalent of `FindReverse' for soft-masked sequences (see `FindForwardMasked')
+func FindReverseMasked(seq, restrict string, seqStart, length, random int, stopCodon bool) (string, MaskReport, error) {
+	report := MaskReport{Length: length}
+	if (seqStart >= 1) && (seqStart+length-1 <= len(seq)) && (length >= MinimumPrimerLength) && (length <= MaximumPrimerLength) {
+		report = chooseMaskedLength(length, len(seq)-seqStart+1, func(l int) string {
+			return Reverse(seq[len(seq)-seqStart-l+1 : len(seq)-seqStart+1]) /* the 3' end of the primer is the end of the string */
+		})
+	}
+	primer, err := FindReverse(seq, restrict, seqStart, report.Length, random, stopCodon)
+	if err != nil {
+		return "", MaskReport{}, err
+	}
+	return primer, report, nil
+}
+
+// chooseMaskedLength returns the report of the complementary length closest to `length' (and at most `available')
+// whose annealing region `region(l)' (oriented from 5' to 3' of the primer) avoids soft-masked nucleotides best
+func chooseMaskedLength(length, available int, region func(l int) string) MaskReport {
+	maxLength := MaximumPrimerLength
+	if available < maxLength {
+		maxLength = available
+	}
+
+	// lengths are tried in the order `length', `length' + 1, `length' - 1, `length' + 2, ...
+	var endUnmasked *MaskReport
+	for d := 0; (length+d <= maxLength) || (length-d >= MinimumPrimerLength); d++ {
+		lengths := []int{length + d, length - d}
+		if d == 0 {
+			lengths = lengths[:1]
+		}
+		for _, l := range lengths {
+			if (l < MinimumPrimerLength) || (l > maxLength) {
+				continue
+			}
+			r := maskReport(region(l))
+			if r.Masked == 0 {
+				return r
+			}
+			if !r.MaskedEnd && (endUnmasked == nil) {
+				endUnmasked = &r
+			}
+		}
+	}
+	if endUnmasked != nil {
+		return *endUnmasked
+	}
+	return maskReport(region(length))
+}
+
+// maskReport counts the soft-masked nucleotides in the annealing region `s' (oriented from 5' to 3' of the primer)
+func maskReport(s string) MaskReport {
+	r := MaskReport{Length: len(s)}
+	for i := 0; i < len(s); i++ {
+		if IsMasked(s[i]) {
+			r.Masked++
+			if i >= len(s)-MaskedEndLength {
+				r.MaskedEnd = true
+			}
+		}
+	}
+	return r
+}
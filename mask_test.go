@@ -0,0 +1,87 @@
+package cloningprimer
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCaseMasked struct {
+	seq    string
+	want   string
+	report MaskReport
+	err    error
+}
+
+func TestFindForwardMasked(t *testing.T) {
+	cases := []testCaseMasked{
+		// sequences without masked nucleotides are not changed
+		{seq: "ATGGCCAAGGTTCCAGGAACCTTGGAATTTGGGCCCAAA", want: "CTGAATTCATGGCCAAGGTTCCAGGAAC", report: MaskReport{Length: 20}, err: nil},
+		// the annealing region is shortened to avoid the masked nucleotides
+		{seq: "ATGGCCAAGGTTCCAGGAAccttggaTTTGGGCCCAAA", want: "CTGAATTCATGGCCAAGGTTCCAGGAA", report: MaskReport{Length: 19}, err: nil},
+		// masked nucleotides at the 5' end cannot be avoided, but the 3' end is not masked
+		{seq: "atgGCCAAGGTTCCAGGAACCTTGGAATTTGGGCCCAAA", want: "CTGAATTCATGGCCAAGGTTCCAGGAAC", report: MaskReport{Length: 20, Masked: 3}, err: nil},
+		// fall back onto masked sequence
+		{seq: "atggccaaggttccaggaaccttgg", want: "CTGAATTCATGGCCAAGGTTCCAGGAAC", report: MaskReport{Length: 20, Masked: 20, MaskedEnd: true}, err: nil},
+		// invalid input is reported like by `FindForward'
+		{seq: "atggcc", want: "", report: MaskReport{}, err: errors.New("invalid input length = 20, must be an integer value >= 10 and smaller than the length of the given sequence (as well as <= the maximum primer length of 30)")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, report, err := FindForwardMasked(c.seq, "GAATTC", 1, 20, 2, true)
+
+		// test similarity of expected and received value
+		if (got != c.want) || (report != c.report) {
+			t.Errorf("FindForwardMasked(%v) == %v, %+v, want %v, %+v\n", c.seq, got, report, c.want, c.report)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("FindForwardMasked(%v) == %v, want %v\n", c.seq, err, c.err)
+		}
+	}
+}
+
+func TestFindReverseMasked(t *testing.T) {
+	cases := []testCaseMasked{
+		// the 3' end of the reverse primer binds to the 5' end of its annealing region in `seq'
+		{seq: "GGCCAAGGTTCCAGGAACCTggcTTGGAATTTGGGCCTAA", want: "CTAAGCTTTTAGGCCCAAATTCCAA", report: MaskReport{Length: 17}, err: nil},
+		{seq: "GGCCAAGGTTCCAGGAACCTGGCTTGGAATTTGGGccTAA", want: "CTAAGCTTTTAGGCCCAAATTCCAAGCC", report: MaskReport{Length: 20, Masked: 2}, err: nil},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, report, err := FindReverseMasked(c.seq, "AAGCTT", 1, 20, 2, true)
+
+		// test similarity of expected and received value
+		if (got != c.want) || (report != c.report) {
+			t.Errorf("FindReverseMasked(%v) == %v, %+v, want %v, %+v\n", c.seq, got, report, c.want, c.report)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("FindReverseMasked(%v) == %v, want %v\n", c.seq, err, c.err)
+		}
+	}
+}
+
+func TestMaskReportMessage(t *testing.T) {
+	cases := []struct {
+		in   MaskReport
+		want string
+	}{
+		{in: MaskReport{Length: 20}, want: ""},
+		{in: MaskReport{Length: 20, Masked: 3}, want: "the forward primer had to be placed on soft-masked sequence: 3 of its 20 complementary nucleotides are masked, its 3' end (5 nucleotides) is not masked"},
+		{in: MaskReport{Length: 18, Masked: 18, MaskedEnd: true}, want: "the forward primer had to be placed on soft-masked sequence: 18 of its 18 complementary nucleotides are masked, including its 3' end"},
+	}
+	for _, c := range cases {
+		if got := c.in.Message("forward"); got != c.want {
+			t.Errorf("%+v.Message(forward) == %v, want %v\n", c.in, got, c.want)
+		}
+	}
+
+	// `ValidateSequenceKeepCase' preserves soft-masked nucleotides
+	if got, err := ValidateSequenceKeepCase([]byte("ATG gcc\nTAA\n")); (got != "ATGgccTAA") || (err != nil) {
+		t.Errorf("ValidateSequenceKeepCase() == %v, %v, want ATGgccTAA, <nil>\n", got, err)
+	}
+}
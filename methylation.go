@@ -0,0 +1,136 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// methylationMotif describes a methylation motif and the positions (0-based, on the top strand) of the bases
+// that are methylated on either strand
+type methylationMotif struct {
+	name       string /* name of the methyltransferase, e.g. Dam */
+	motif      string /* recognition sequence of the methyltransferase in IUPAC notation */
+	strain     string /* E. coli genotype that lacks the methyltransferase */
+	methylated []int  /* offsets of methylated bases within the motif (both strands) */
+}
+
+// damMotif is the Dam methylation motif (GATC, the adenine is methylated on both strands)
+var damMotif = methylationMotif{name: "Dam", motif: "GATC", strain: "dam-", methylated: []int{1, 2}}
+
+// dcmMotif is the Dcm methylation motif (CCWGG, the internal cytosine is methylated on both strands)
+var dcmMotif = methylationMotif{name: "Dcm", motif: "CCWGG", strain: "dcm-", methylated: []int{1, 3}}
+
+// MethylationWarning describes an occurrence of a recognition site that overlaps a Dam or Dcm methylation motif
+type MethylationWarning struct {
+	Enzyme      string                 /* name of the restriction enzyme */
+	Site        int                    /* position of the recognition site in the sequence (1-based) */
+	Motif       string                 /* name of the overlapping methylation, i.e. "Dam" or "Dcm" */
+	Sensitivity MethylationSensitivity /* sensitivity of the enzyme to the overlapping methylation */
+	Strain      string                 /* E. coli genotype that must be used to prepare the DNA, e.g. "dam-" */
+}
+
+// String returns a human readable description of the warning
+func (w MethylationWarning) String() string {
+	return fmt.Sprintf("%s site at position %d overlaps %s methylation (%s): prepare the plasmid from a %s E. coli strain", w.Enzyme, w.Site, w.Motif, w.Sensitivity, w.Strain)
+}
+
+// CheckMethylation returns a warning for every occurrence of the recognition site of `enzyme' in `seq' that overlaps a
+// Dam or Dcm methylation motif, if `enzyme' is known to be blocked or impaired by the respective methylation; `seq' can be
+// a template or a designed primer (the primer contains the entire context of the restriction site in the PCR product)
+func CheckMethylation(seq string, enzyme RestrictEnzyme) []MethylationWarning {
+	var warnings []MethylationWarning
+	checks := []struct {
+		motif       *methylationMotif
+		sensitivity MethylationSensitivity
+	}{{&damMotif, enzyme.Dam}, {&dcmMotif, enzyme.Dcm}}
+	seq = strings.ToUpper(seq)
+	n := len(enzyme.RecognitionSite)
+	for _, site := range FindSites(seq, enzyme.RecognitionSite) {
+		for _, c := range checks {
+			if (c.sensitivity != MethylationBlocked) && (c.sensitivity != MethylationImpaired) {
+				continue
+			}
+			if overlapsMethylation(seq, site-1, n, c.motif) {
+				warnings = append(warnings, MethylationWarning{
+					Enzyme:      enzyme.Name,
+					Site:        site,
+					Motif:       c.motif.name,
+					Sensitivity: c.sensitivity,
+					Strain:      c.motif.strain,
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// methylationWarnings returns the warnings of `CheckMethylation' for all `enzymes' (an enzyme that is listed more than
+// once is only checked once) in `seq' as strings that are prefixed with `label'
+func methylationWarnings(label, seq string, enzymes ...RestrictEnzyme) []string {
+	var warnings []string
+	checked := make(map[string]bool)
+	for _, e := range enzymes {
+		if checked[e.Name] {
+			continue
+		}
+		checked[e.Name] = true
+		for _, w := range CheckMethylation(seq, e) {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", label, w))
+		}
+	}
+	return warnings
+}
+
+// FindSites returns the 1-based positions of all occurrences of a recognition `site' (which may contain IUPAC codes)
+// in `seq'; for sites that are not palindromic, occurrences on the bottom strand are reported by the position of the
+// reverse complement of `site' on the top strand
+func FindSites(seq, site string) []int {
+	var positions []int
+	seq = strings.ToUpper(seq)
+	site = strings.ToUpper(site)
+	if (site == "") || (len(site) > len(seq)) {
+		return positions
+	}
+	rc := reverseComplementIUPAC(site)
+	for i := 0; i <= len(seq)-len(site); i++ {
+		if matchesIUPAC(seq[i:i+len(site)], site) || ((rc != site) && matchesIUPAC(seq[i:i+len(site)], rc)) {
+			positions = append(positions, i+1)
+		}
+	}
+	return positions
+}
+
+// overlapsMethylation returns true if a methylated base of motif `m' lies within `seq[start:start+length]'
+func overlapsMethylation(seq string, start, length int, m *methylationMotif) bool {
+	for i := start - len(m.motif) + 1; i < start+length; i++ {
+		if (i < 0) || (i+len(m.motif) > len(seq)) || !matchesIUPAC(seq[i:i+len(m.motif)], m.motif) {
+			continue
+		}
+		for _, offset := range m.methylated {
+			if (i+offset >= start) && (i+offset < start+length) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesIUPAC returns true if the nucleotide sequence `seq' matches `pattern' (which may contain IUPAC codes)
+func matchesIUPAC(seq, pattern string) bool {
+	if len(seq) != len(pattern) {
+		return false
+	}
+	for i := 0; i < len(seq); i++ {
+		if !strings.ContainsRune(iupacCodes[pattern[i]], rune(seq[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// iupacCodes maps IUPAC nucleotide codes to the nucleotides they stand for
+var iupacCodes = map[byte]string{
+	'A': "A", 'C': "C", 'G': "G", 'T': "T",
+	'R': "AG", 'Y': "CT", 'S': "CG", 'W': "AT", 'K': "GT", 'M': "AC",
+	'B': "CGT", 'D': "AGT", 'H': "ACT", 'V': "ACG", 'N': "ACGT",
+}
@@ -0,0 +1,125 @@
+package cloningprimer
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testCaseFindSites struct {
+	in   findSitesInput
+	want []int
+}
+
+type findSitesInput struct {
+	seq  string
+	site string
+}
+
+type testCaseMethylation struct {
+	in   methylationInput
+	want []string
+}
+
+type methylationInput struct {
+	seq    string
+	enzyme RestrictEnzyme
+}
+
+func TestFindSites(t *testing.T) {
+	cases := []testCaseFindSites{
+		// test palindromic site
+		{
+			in:   findSitesInput{"AAGAATTCAAGAATTC", "GAATTC"},
+			want: []int{3, 11},
+		},
+		// test site with IUPAC codes and lower case input
+		{
+			in:   findSitesInput{"ttccaggttcctgg", "CCWGG"},
+			want: []int{3, 10},
+		},
+		// test non-palindromic site on both strands (GGTCTC and GAGACC)
+		{
+			in:   findSitesInput{"AGGTCTCAAAGAGACCA", "GGTCTC"},
+			want: []int{2, 11},
+		},
+		// test site that is longer than the sequence
+		{
+			in:   findSitesInput{"GAAT", "GAATTC"},
+			want: nil,
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got := FindSites(c.in.seq, c.in.site)
+		if len(got) != len(c.want) {
+			t.Errorf("FindSites(%v, %v) == %v, want %v\n", c.in.seq, c.in.site, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("FindSites(%v, %v) == %v, want %v\n", c.in.seq, c.in.site, got, c.want)
+			}
+		}
+	}
+}
+
+func TestPrimerPairMethylationWarnings(t *testing.T) {
+	// sites in the template are reported once per enzyme
+	xbaI := RestrictEnzyme{Name: "XbaI", RecognitionSite: "TCTAGA", Dam: MethylationBlocked}
+	p := PrimerPair{Forward: "AATCTAGATCATGGCTAAAGAG", Reverse: "AATCTAGAAAGCGTTTCACCAG", EnzymeF: xbaI, EnzymeR: xbaI}
+	want := []string{
+		"forward primer: XbaI site at position 3 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain",
+		"template: XbaI site at position 7 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain",
+	}
+	if got := p.MethylationWarnings("ATGGCTTCTAGATCAAAG"); !reflect.DeepEqual(got, want) {
+		t.Errorf("MethylationWarnings() == %q, want %q\n", got, want)
+	}
+}
+
+func TestCheckMethylation(t *testing.T) {
+	xbaI := RestrictEnzyme{Name: "XbaI", RecognitionSite: "TCTAGA", Dam: MethylationBlocked, Dcm: MethylationNotSensitive}
+	claI := RestrictEnzyme{Name: "ClaI", RecognitionSite: "ATCGAT", Dam: MethylationBlocked}
+	stuI := RestrictEnzyme{Name: "StuI", RecognitionSite: "AGGCCT", Dcm: MethylationBlocked}
+	cases := []testCaseMethylation{
+		// XbaI followed by TC forms GATC
+		{
+			in:   methylationInput{"AATCTAGATCAA", xbaI},
+			want: []string{"XbaI site at position 3 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain"},
+		},
+		// XbaI without overlapping GATC
+		{
+			in:   methylationInput{"AATCTAGAAA", xbaI},
+			want: nil,
+		},
+		// ClaI preceded by G forms GATC at its 5' end
+		{
+			in:   methylationInput{"AGATCGATA", claI},
+			want: []string{"ClaI site at position 3 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain"},
+		},
+		// StuI followed by GG forms CCWGG (CCTGG)
+		{
+			in:   methylationInput{"AAGGCCTGGA", stuI},
+			want: []string{"StuI site at position 2 overlaps Dcm methylation (blocked): prepare the plasmid from a dcm- E. coli strain"},
+		},
+		// enzymes without methylation data never produce warnings
+		{
+			in:   methylationInput{"AATCTAGATCAA", RestrictEnzyme{Name: "XbaI", RecognitionSite: "TCTAGA"}},
+			want: nil,
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got := CheckMethylation(c.in.seq, c.in.enzyme)
+		if len(got) != len(c.want) {
+			t.Errorf("CheckMethylation(%v, %v) == %v, want %v\n", c.in.seq, c.in.enzyme.Name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i].String() != c.want[i] {
+				t.Errorf("CheckMethylation(%v, %v) == %v, want %v\n", c.in.seq, c.in.enzyme.Name, got, c.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QuikChangeTm gives the minimum Tm (in °C, see `CalculateTmQuikChange') of QuikChange primers
+	QuikChangeTm = 78.0
+
+	// QuikChangeMinLength and QuikChangeMaxLength give the length range of QuikChange primers
+	QuikChangeMinLength = 25
+	QuikChangeMaxLength = 45
+
+	// Q5AnnealTm gives the Tm (in °C, see `CalculateTmNN') that the annealing parts of Q5 site-directed mutagenesis
+	// primers should reach
+	Q5AnnealTm = 60.0
+
+	// Q5MaxTail gives the number of inserted nucleotides up to which an insertion is added to the forward primer only;
+	// longer insertions are split between both primers
+	Q5MaxTail = 6
+
+	// MaxInsertion gives the maximum number of nucleotides that a mutation can insert
+	MaxInsertion = 30
+)
+
+// SaturationCodons lists the degenerate codons (IUPAC notation) that can replace a codon to saturate its position:
+// NNN encodes all 64 codons, NNK and NNS encode 32 codons with all 20 amino acids and only one stop codon
+var SaturationCodons = map[string]bool{"NNN": true, "NNK": true, "NNS": true}
+
+// RareCodons lists codons that are rarely used in E. coli and are avoided when an amino acid change is translated into
+// a codon
+var RareCodons = map[string]bool{"AGA": true, "AGG": true, "CGA": true, "CGG": true, "CTA": true, "ATA": true, "CCC": true}
+
+var (
+	aminoAcidChangeRegexp = regexp.MustCompile(`^(?:P\.)?([ACDEFGHIKLMNPQRSTVWY*])(\d+)([ACDEFGHIKLMNPQRSTVWY*]|NN[NKS])$`)
+	substitutionRegexp    = regexp.MustCompile(`^(?:C\.)?(\d+)([ACGT])>([ACGT])$`)
+	deletionRegexp        = regexp.MustCompile(`^(?:C\.)?(\d+)(?:_(\d+))?DEL(?:INS([ACGT]+))?$`)
+	insertionRegexp       = regexp.MustCompile(`^(?:C\.)?(\d+)_(\d+)INS([ACGT]+)$`)
+)
+
+// Mutation is a change of a template sequence: `Deleted' nucleotides starting at `Position' are replaced by the
+// `Inserted' nucleotides (an insertion deletes no nucleotides and is placed in front of `Position')
+type Mutation struct {
+	Spec      string /* the mutation as it was requested, e.g. R175H or 523A>G */
+	Position  int    /* 1-based position of the first changed nucleotide of the template */
+	Deleted   int    /* number of deleted template nucleotides */
+	Inserted  string /* nucleotides that replace the deleted ones */
+	Codon     int    /* number of the changed codon (amino acid changes only) */
+	From      string /* original codon (amino acid changes only) */
+	To        string /* new codon (amino acid changes only) */
+	Changes   int    /* number of changed nucleotides of a substitution */
+	Reference string /* the deleted template nucleotides */
+}
+
+// String returns the mutation in the notation in which it was requested, followed by the changed codons for amino
+// acid changes, e.g. R175H (CGC>CAC)
+func (m Mutation) String() string {
+	if m.From != "" {
+		return fmt.Sprintf("%s (%s>%s)", m.Spec, m.From, m.To)
+	}
+	return m.Spec
+}
+
+// substitution returns true if `m' replaces nucleotides without changing the length of the template
+func (m Mutation) substitution() bool {
+	return m.Deleted == len(m.Inserted)
+}
+
+// ResolveMutation parses the mutation `spec' and resolves it against `template' (whose reading frame starts at the
+// 1-based position `frame'); mutations are written like in the HGVS nomenclature: nucleotide substitutions (523A>G),
+// amino acid changes (R175H, '*' for a stop codon), deletions (123del, 123_125del), insertions (123_124insGGC) and
+// deletion-insertions (123_125delinsAT), optionally prefixed by 'c.' or 'p.'; positions count from the start of
+// `template' and codons from `frame'; the codon of an amino acid change is the one with the fewest changed nucleotides
+// (codons in `RareCodons' are only used if there is no other choice); a codon can also be replaced by one of the
+// `SaturationCodons' (e.g. R175NNK)
+func ResolveMutation(template, spec string, frame int) (Mutation, error) {
+	template = strings.ToUpper(template)
+	s := strings.ToUpper(strings.TrimSpace(spec))
+	m := Mutation{Spec: strings.TrimSpace(spec)}
+	var first, last int
+	switch {
+	case aminoAcidChangeRegexp.MatchString(s):
+		match := aminoAcidChangeRegexp.FindStringSubmatch(s)
+		m.Codon, _ = strconv.Atoi(match[2])
+		start := frame - 1 + (m.Codon-1)*Codon
+		if (frame < 1) || (m.Codon < 1) || (start+Codon > len(template)) {
+			return Mutation{}, fmt.Errorf("invalid input: codon %d of a reading frame starting at position %d is outside of the template", m.Codon, frame)
+		}
+		m.From = template[start : start+Codon]
+		if aa, ok := GeneticCode[m.From]; !ok || (aa != match[1][0]) {
+			return Mutation{}, fmt.Errorf("invalid input: codon %d is %s (%c), not %s", m.Codon, m.From, GeneticCode[m.From], match[1])
+		}
+		m.To = match[3]
+		if !SaturationCodons[m.To] {
+			m.To = closestCodon(m.From, match[3][0])
+		}
+		if m.To == m.From {
+			return Mutation{}, fmt.Errorf("invalid input: %s does not change the amino acid", m.Spec)
+		}
+		m.Position, m.Deleted, m.Inserted = start+1, Codon, m.To
+	case substitutionRegexp.MatchString(s):
+		match := substitutionRegexp.FindStringSubmatch(s)
+		first, _ = strconv.Atoi(match[1])
+		if (first < 1) || (first > len(template)) {
+			return Mutation{}, fmt.Errorf("invalid input: position %d is outside of the template (1-%d)", first, len(template))
+		}
+		if template[first-1] != match[2][0] {
+			return Mutation{}, fmt.Errorf("invalid input: position %d of the template is %c, not %s", first, template[first-1], match[2])
+		}
+		m.Position, m.Deleted, m.Inserted = first, 1, match[3]
+	case deletionRegexp.MatchString(s):
+		match := deletionRegexp.FindStringSubmatch(s)
+		first, _ = strconv.Atoi(match[1])
+		last = first
+		if match[2] != "" {
+			last, _ = strconv.Atoi(match[2])
+		}
+		m.Position, m.Deleted, m.Inserted = first, last-first+1, match[3]
+	case insertionRegexp.MatchString(s):
+		match := insertionRegexp.FindStringSubmatch(s)
+		first, _ = strconv.Atoi(match[1])
+		last, _ = strconv.Atoi(match[2])
+		if last != first+1 {
+			return Mutation{}, fmt.Errorf("invalid input: an insertion must be placed between two adjacent positions, not %d and %d", first, last)
+		}
+		m.Position, m.Inserted = last, match[3]
+	default:
+		return Mutation{}, fmt.Errorf("invalid input: cannot parse mutation %q (expected e.g. 523A>G, R175H, 123_125del or 123_124insGGC)", m.Spec)
+	}
+	switch {
+	case (m.Deleted < 0) || ((m.Deleted == 0) && (m.Inserted == "")):
+		return Mutation{}, fmt.Errorf("invalid input: mutation %s does not change the template", m.Spec)
+	case (m.Position < 1) || (m.Position+m.Deleted-1 > len(template)) || ((m.Deleted == 0) && (m.Position > len(template))):
+		return Mutation{}, fmt.Errorf("invalid input: mutation %s is outside of the template (1-%d)", m.Spec, len(template))
+	case len(m.Inserted) > MaxInsertion:
+		return Mutation{}, fmt.Errorf("invalid input: mutation %s inserts %d nucleotides, expected at most %d", m.Spec, len(m.Inserted), MaxInsertion)
+	}
+	m.Reference = template[m.Position-1 : m.Position-1+m.Deleted]
+	if m.substitution() {
+		for i := range m.Inserted {
+			if m.Inserted[i] != m.Reference[i] {
+				m.Changes++
+			}
+		}
+	}
+	return m, nil
+}
+
+// closestCodon returns the codon of amino acid `aa' with the fewest nucleotide changes compared to `codon' (a codon in
+// `RareCodons' is only returned if there is no other codon with the same number of changes); `codon' itself is
+// returned if it already encodes `aa'
+func closestCodon(codon string, aa byte) string {
+	if GeneticCode[codon] == aa {
+		return codon
+	}
+	var best string
+	bestChanges := Codon + 1
+	for _, c := range append(SynonymousCodons(codonOf(aa)), codonOf(aa)) {
+		var changes int
+		for i := 0; i < Codon; i++ {
+			if c[i] != codon[i] {
+				changes++
+			}
+		}
+		better := changes < bestChanges
+		if changes == bestChanges {
+			better = (RareCodons[best] && !RareCodons[c]) || ((RareCodons[best] == RareCodons[c]) && (c < best))
+		}
+		if better {
+			best, bestChanges = c, changes
+		}
+	}
+	return best
+}
+
+// codonOf returns a codon of the amino acid `aa'
+func codonOf(aa byte) string {
+	for i := 0; i < len(codonTable); i++ {
+		if codonTable[i] == aa {
+			return string([]byte{codonBases[i/16], codonBases[i/4%4], codonBases[i%4]})
+		}
+	}
+	return ""
+}
+
+// CalculateTmQuikChange calculates the Tm (in °C) of a QuikChange `primer' with the formula of the QuikChange manual,
+// Tm = 81.5 + 0.41 * %GC - 675 / N - %mismatch, where N is the primer length without the `inserted' nucleotides and
+// %mismatch is the percentage of `mismatches' (used for substitutions)
+func CalculateTmQuikChange(primer string, mismatches, inserted int) float64 {
+	n := len(primer) - inserted
+	if n <= 0 {
+		return 0
+	}
+	gc := 100 * float64(strings.Count(strings.ToUpper(primer), "G")+strings.Count(strings.ToUpper(primer), "C")) / float64(len(primer))
+	return 81.5 + 0.41*gc - 675/float64(n) - 100*float64(mismatches)/float64(len(primer))
+}
+
+// MutagenesisDesign holds the primers that introduce a mutation into a template by QuikChange (overlapping primers
+// that amplify the complete plasmid) and by Q5 site-directed mutagenesis (back-to-back primers whose product is
+// phosphorylated and ligated)
+type MutagenesisDesign struct {
+	Mutation   Mutation         /* the resolved mutation */
+	Template   string           /* the validated template */
+	Mutated    string           /* the template with the mutation */
+	QuikChange []AssemblyPrimer /* complementary forward and reverse primers with the mutation in the middle */
+	Q5         []AssemblyPrimer /* back-to-back forward and reverse primers (see `DesignMutagenesis') */
+	Warnings   []string         /* primers that do not meet all requirements and rare codons */
+}
+
+// Record returns the mutated template as a GenBank record named `name' with a feature for the mutation
+func (d MutagenesisDesign) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("%s with mutation %s", name, d.Mutation),
+		Molecule:   "DNA",
+		Topology:   "circular",
+		Division:   "SYN",
+		Sequence:   d.Mutated,
+	}
+	r.Features = append(r.Features, syntheticSource(len(d.Mutated)))
+	start, end := d.Mutation.Position, d.Mutation.Position+len(d.Mutation.Inserted)-1
+	if end < start { /* a deletion is marked by the nucleotide in front of it */
+		start, end = start-1, start-1
+	}
+	if start >= 1 {
+		r.Features = append(r.Features, NewFeature("variation", start, end, false, Qualifier{Key: "label", Value: d.Mutation.Spec}, Qualifier{Key: "note", Value: d.Mutation.String()}))
+	}
+	return r
+}
+
+// DesignMutagenesis designs QuikChange and Q5 site-directed mutagenesis primers that introduce the mutation `spec'
+// (see `ResolveMutation') into the circular plasmid `template', whose reading frame starts at the 1-based position `frame';
+// QuikChange primers are extended alternately on both sides of the mutation until they reach `QuikChangeTm' and
+// `QuikChangeMinLength' (at most `QuikChangeMaxLength') and, if possible, start and end with G or C; like in NEB's
+// guidelines, the forward Q5 primer of a substitution has the changed nucleotides in its centre (see `centredFlank') and
+// the forward Q5 primer of an insertion or deletion starts with the inserted nucleotides (insertions of more than
+// `Q5MaxTail' nucleotides are split between both primers); the reverse Q5 primer binds directly upstream of the
+// forward primer
+func DesignMutagenesis(template FastaRecord, spec string, frame int) (MutagenesisDesign, error) {
+	seq, err := ValidateSequence([]byte(template.Sequence))
+	if err != nil {
+		return MutagenesisDesign{}, fmt.Errorf("invalid sequence: %v", err)
+	}
+	m, err := ResolveMutation(seq, spec, frame)
+	if err != nil {
+		return MutagenesisDesign{}, err
+	}
+	if template.Name == "" {
+		template.Name = "template"
+	}
+	d := MutagenesisDesign{Mutation: m, Template: seq}
+	p := m.Position - 1 /* 0-based start of the mutation */
+	d.Mutated = seq[:p] + m.Inserted + seq[p+m.Deleted:]
+	if RareCodons[m.To] {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("the new codon %s is rarely used in E. coli", m.To))
+	}
+	d.addQuikChangePrimers(template.Name)
+	d.addQ5Primers(template.Name)
+	return d, nil
+}
+
+// addQuikChangePrimers adds the QuikChange primers of `d' (named after the template `name')
+func (d *MutagenesisDesign) addQuikChangePrimers(name string) {
+	// the plasmid is rotated such that the mutation is in its middle
+	m, n := d.Mutation, len(d.Mutated)
+	shift := ((m.Position-1-n/2)%n + n) % n
+	seq := d.Mutated[shift:] + d.Mutated[:shift]
+	start := ((m.Position-1-shift)%n + n) % n
+	end := start + len(m.Inserted) /* the primer is seq[start:end] */
+	mismatches, inserted := m.Changes, 0
+	if !m.substitution() {
+		mismatches, inserted = 0, len(m.Inserted)
+	}
+	tm := func() float64 { return CalculateTmQuikChange(seq[start:end], mismatches, inserted) }
+	for left := true; (end-start < QuikChangeMaxLength) && ((start > 0) || (end < n)); left = !left {
+		if (end-start >= QuikChangeMinLength) && (tm() >= QuikChangeTm) {
+			break
+		}
+		if (left && (start > 0)) || (end == n) {
+			start--
+		} else {
+			end++
+		}
+	}
+
+	// extend the primer by up to 2 nucleotides per side to start and end with G or C
+	gc := func(b byte) bool { return (b == 'G') || (b == 'C') }
+	for i := 1; (i <= 2) && !gc(seq[start]) && (start-i >= 0) && (end-start+i <= QuikChangeMaxLength); i++ {
+		if gc(seq[start-i]) {
+			start -= i
+		}
+	}
+	for i := 1; (i <= 2) && !gc(seq[end-1]) && (end+i <= n) && (end-start+i <= QuikChangeMaxLength); i++ {
+		if gc(seq[end+i-1]) {
+			end += i
+		}
+	}
+	primer := seq[start:end]
+	t := math.Round(tm()*10) / 10
+	d.QuikChange = []AssemblyPrimer{{Name: name + "_QC_F", Sequence: primer, Tm: t}, {Name: name + "_QC_R", Sequence: reverseComplementIUPAC(primer), Tm: t}}
+	if t < QuikChangeTm {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("the QuikChange primers only reach a Tm of %.1f°C", t))
+	}
+	if !gc(primer[0]) || !gc(primer[len(primer)-1]) {
+		d.Warnings = append(d.Warnings, "the QuikChange primers do not start and end with G or C")
+	}
+}
+
+// addQ5Primers adds the back-to-back Q5 site-directed mutagenesis primers of `d' (named after the template `name')
+func (d *MutagenesisDesign) addQ5Primers(name string) {
+	m := d.Mutation
+	p := m.Position - 1
+	tailF, tailR := m.Inserted, ""
+	if !m.substitution() && (len(m.Inserted) > Q5MaxTail) {
+		h := len(m.Inserted) / 2
+		tailF, tailR = m.Inserted[h:], reverseComplementIUPAC(m.Inserted[:h])
+	}
+	circular := d.Template[p+m.Deleted:] + d.Template[:p] /* the plasmid without the deleted nucleotides */
+	forward := []tailedPrimer{{name + "_Q5_F", circular, tailF}}
+	flank := 0 /* complementary nucleotides on each side of a substitution */
+	if m.substitution() {
+		var tm float64
+		flank, tm = centredFlank(circular, Q5AnnealTm)
+		d.Q5, forward = []AssemblyPrimer{{Name: name + "_Q5_F", Sequence: circular[len(circular)-flank:] + m.Inserted + circular[:flank], Tm: tm}}, nil
+		if tm < Q5AnnealTm {
+			d.Warnings = append(d.Warnings, annealingWarning(name+"_Q5_F", tm))
+		}
+	}
+	primers, warnings := tailedPrimers(Q5AnnealTm, append(forward, tailedPrimer{name + "_Q5_R", reverseComplementIUPAC(circular[:len(circular)-flank]), tailR})...)
+	d.Q5 = append(d.Q5, primers...)
+	d.Warnings = append(d.Warnings, warnings...)
+	if diff := math.Abs(d.Q5[0].Tm - d.Q5[1].Tm); diff > 5 {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("the annealing parts of the Q5 primers differ by %.1f°C (more than 5°C)", diff))
+	}
+}
+
+// centredFlank returns the number of complementary nucleotides on each side of a substitution in the forward Q5 primer
+// and their Tm (see `CalculateTmNN'): both sides have the same length (at least `MinimumPrimerLength', so that at least
+// 10 nucleotides anneal 3' of the mismatch) and are extended until they reach `tm' together; `circular' is the plasmid
+// that starts behind the substituted nucleotides
+func centredFlank(circular string, tm float64) (int, float64) {
+	var flank int
+	var flankTm float64
+	for l := MinimumPrimerLength; (l <= MaximumPrimerLength) && (2*l <= len(circular)); l++ {
+		flank = l
+		flankTm, _ = CalculateTmNN(circular[len(circular)-l:] + circular[:l])
+		if flankTm >= tm {
+			break
+		}
+	}
+	return flank, flankTm
+}
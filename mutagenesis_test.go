@@ -0,0 +1,128 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResolveMutation(t *testing.T) {
+	cases := []struct {
+		spec string
+		want Mutation
+		err  error
+	}{
+		// the codon with the fewest changes is chosen (GAC and GAT need one change each)
+		{spec: "E4D", want: Mutation{Spec: "E4D", Position: 10, Deleted: 3, Inserted: "GAC", Codon: 4, From: "GAG", To: "GAC", Changes: 1, Reference: "GAG"}, err: nil},
+		{spec: "p.E4*", want: Mutation{Spec: "p.E4*", Position: 10, Deleted: 3, Inserted: "TAG", Codon: 4, From: "GAG", To: "TAG", Changes: 1, Reference: "GAG"}, err: nil},
+		// rare codons are used if no other codon needs as few changes
+		{spec: "K3R", want: Mutation{Spec: "K3R", Position: 7, Deleted: 3, Inserted: "AGA", Codon: 3, From: "AAA", To: "AGA", Changes: 1, Reference: "AAA"}, err: nil},
+		// degenerate codons replace the complete codon
+		{spec: "K3nnk", want: Mutation{Spec: "K3nnk", Position: 7, Deleted: 3, Inserted: "NNK", Codon: 3, From: "AAA", To: "NNK", Changes: 3, Reference: "AAA"}, err: nil},
+		{spec: "c.10G>A", want: Mutation{Spec: "c.10G>A", Position: 10, Deleted: 1, Inserted: "A", Changes: 1, Reference: "G"}, err: nil},
+		{spec: "20_25del", want: Mutation{Spec: "20_25del", Position: 20, Deleted: 6, Reference: "TGAAAC"}, err: nil},
+		{spec: "12del", want: Mutation{Spec: "12del", Position: 12, Deleted: 1, Reference: "G"}, err: nil},
+		{spec: "30_31insCAT", want: Mutation{Spec: "30_31insCAT", Position: 31, Inserted: "CAT"}, err: nil},
+		{spec: "50_52delinsTT", want: Mutation{Spec: "50_52delinsTT", Position: 50, Deleted: 3, Inserted: "TT", Reference: "TGG"}, err: nil},
+		{spec: "R26H", want: Mutation{}, err: errors.New("invalid input: codon 26 is GTT (V), not R")},
+		{spec: "K3K", want: Mutation{}, err: errors.New("invalid input: K3K does not change the amino acid")},
+		{spec: "10A>G", want: Mutation{}, err: errors.New("invalid input: position 10 of the template is G, not A")},
+		{spec: "30_32insCAT", want: Mutation{}, err: errors.New("invalid input: an insertion must be placed between two adjacent positions, not 30 and 32")},
+		{spec: "125_130del", want: Mutation{}, err: errors.New("invalid input: mutation 125_130del is outside of the template (1-129)")},
+		{spec: "R175", want: Mutation{}, err: errors.New(`invalid input: cannot parse mutation "R175" (expected e.g. 523A>G, R175H, 123_125del or 123_124insGGC)`)},
+	}
+	for _, c := range cases {
+		got, err := ResolveMutation(testCDS, c.spec, 1)
+		if got != c.want {
+			t.Errorf("ResolveMutation(%v) == %#v, want %#v\n", c.spec, got, c.want)
+		}
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ResolveMutation(%v) == %v, want %v\n", c.spec, err, c.err)
+		}
+	}
+}
+
+func TestCalculateTmQuikChange(t *testing.T) {
+	cases := []struct {
+		primer     string
+		mismatches int
+		inserted   int
+		want       float64
+	}{
+		// 46.67% GC, 30 nucleotides, 1 mismatch: 81.5 + 19.13 - 22.5 - 3.33
+		{primer: "ATGCATGCATGCATGCATGCATGCATGCAT", mismatches: 1, inserted: 0, want: 74.80},
+		// the inserted nucleotides do not count for N: 81.5 + 19.13 - 27
+		{primer: "ATGCATGCATGCATGCATGCATGCATGCAT", mismatches: 0, inserted: 5, want: 73.63},
+	}
+	for _, c := range cases {
+		if got := CalculateTmQuikChange(c.primer, c.mismatches, c.inserted); (got < c.want-0.01) || (got > c.want+0.01) {
+			t.Errorf("CalculateTmQuikChange(%v, %d, %d) == %.2f, want %.2f\n", c.primer, c.mismatches, c.inserted, got, c.want)
+		}
+	}
+}
+
+func TestQ5Substitution(t *testing.T) {
+	// the substitution is centred in the forward primer with at least 10 complementary nucleotides 3' of it and the
+	// reverse primer binds directly upstream of the forward primer
+	for _, spec := range []string{"K3R", "R15A", "K20A", "57T>C"} {
+		d, err := DesignMutagenesis(FastaRecord{Name: "gene", Sequence: testCDS}, spec, 1)
+		if err != nil {
+			t.Fatalf("DesignMutagenesis(%v) == %v, want <nil>\n", spec, err)
+		}
+		n, p, m := len(testCDS), d.Mutation.Position-1, d.Mutation
+		plasmid := testCDS + testCDS + testCDS /* the template is circular */
+		f, r := d.Q5[0].Sequence, d.Q5[1].Sequence
+		flank := (len(f) - len(m.Inserted)) / 2
+		up, down := plasmid[n+p-flank:n+p], plasmid[n+p+m.Deleted:n+p+m.Deleted+flank]
+		if (flank < 10) || (f != up+m.Inserted+down) {
+			t.Errorf("Q5 forward primer of %v == %v, want %v + %v + %v with at least 10 nucleotides on each side\n", spec, f, up, m.Inserted, down)
+		}
+		if want := reverseComplementIUPAC(plasmid[n+p-flank-len(r) : n+p-flank]); r != want {
+			t.Errorf("Q5 reverse primer of %v == %v, want %v\n", spec, r, want)
+		}
+	}
+}
+
+func TestDesignMutagenesis(t *testing.T) {
+	cases := []struct {
+		spec     string
+		primers  []string
+		warnings []string
+		err      error
+	}{
+		// the template is a plasmid, so the primers of a mutation close to its start wrap around
+		{
+			spec:     "K3R",
+			primers:  []string{"GAAACGCTAAATGGCTAGAGAGACCGTTCTGAAAC", "GTTTCAGAACGGTCTCTCTAGCCATTTAGCGTTTC", "CGCTAAATGGCTAGAGAGACCGTTCTG", "TTTCACCAGACCTTCTTTACGGGACAG"},
+			warnings: []string{"the new codon AGA is rarely used in E. coli"},
+			err:      nil,
+		},
+		{
+			spec:     "20_25del",
+			primers:  []string{"CTAAAGAGACCGTTCGTATCGCTGAAGAAC", "GTTCTTCAGCGATACGAACGGTCTCTTTAG", "GTATCGCTGAAGAACTGCGTGCTC", "GAACGGTCTCTTTAGCCATTTAGCGTTTC"},
+			warnings: nil,
+			err:      nil,
+		},
+		// long insertions are split between the Q5 primers
+		{
+			spec:     "30_31insGGCGGCGGCGGC",
+			primers:  []string{"CTGAAACGTATCGGCGGCGGCGGCGCTGAAGAACTG", "CAGTTCTTCAGCGCCGCCGCCGCCGATACGTTTCAG", "GGCGGCGCTGAAGAACTGCGTGCTCTGG", "GCCGCCGATACGTTTCAGAACGGTCTCTTTAGCCA"},
+			warnings: nil,
+			err:      nil,
+		},
+		{spec: "12_11del", primers: nil, warnings: nil, err: errors.New("invalid input: mutation 12_11del does not change the template")},
+	}
+	for _, c := range cases {
+		d, err := DesignMutagenesis(FastaRecord{Name: "gene", Sequence: testCDS}, c.spec, 1)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("DesignMutagenesis(%v) == %v, want %v\n", c.spec, err, c.err)
+		}
+		var primers []string
+		for _, p := range append(d.QuikChange, d.Q5...) {
+			primers = append(primers, p.Sequence)
+		}
+		if !reflect.DeepEqual(primers, c.primers) || !reflect.DeepEqual(d.Warnings, c.warnings) {
+			t.Errorf("DesignMutagenesis(%v) == %v, %q, want %v, %q\n", c.spec, primers, d.Warnings, c.primers, c.warnings)
+		}
+	}
+}
@@ -2,10 +2,9 @@ package cloningprimer
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 )
 
@@ -16,15 +15,105 @@ type RestrictEnzyme struct {
 	NoPalinCleav    string   /* either "no" or "(...)(...)", see *.re specification in ./assets/enzymes.re */
 	ID              string   /* the PDB ID of the enzyme */
 	Isoschizomeres  []string /* common isoschizomeres */
+	TopCut          int      /* top strand cut position counted from the first nucleotide of the recognition site (e.g. 1 for G^AATTC) */
+	BottomCut       int      /* bottom strand cut position, counted on the top strand like `TopCut' (e.g. 5 for G^AATTC) */
+	CutKnown        bool     /* true if `TopCut' and `BottomCut' are known (*.re files of version 2 record them in two columns) */
+	Suppliers       []string /* single letter REBASE codes of commercial suppliers (e.g. "N" for New England Biolabs) */
+	MethylationSite string   /* site that the cognate methyltransferase modifies as reported by REBASE, e.g. "2(5)"; see `Dam', `Dcm' and `CpG' for sensitivity */
+
+	// the following fields are only available in *.re files of version 2 or higher (see `REVersion')
+	Buffer           string                 /* recommended reaction buffer, e.g. "rCutSmart" */
+	BufferActivity   map[string]int         /* activity (in %) per reaction buffer, e.g. {"r2.1": 100, "r3.1": 50} */
+	IncubationTemp   int                    /* incubation temperature in °C, 0 if unknown */
+	InactivationTemp int                    /* heat inactivation temperature in °C, 0 if unknown or if the enzyme cannot be heat inactivated */
+	Dam              MethylationSensitivity /* sensitivity to overlapping Dam methylation (GATC) */
+	Dcm              MethylationSensitivity /* sensitivity to overlapping Dcm methylation (CCWGG) */
+	CpG              MethylationSensitivity /* sensitivity to overlapping CpG methylation */
+	StarActivity     bool                   /* true if the enzyme is known to show star activity */
+}
+
+// MethylationSensitivity describes whether a restriction enzyme still cuts if its recognition site overlaps a methylated motif
+type MethylationSensitivity string
+
+const (
+	// MethylationUnknown indicates that no methylation sensitivity data is available
+	MethylationUnknown MethylationSensitivity = ""
+
+	// MethylationNotSensitive indicates that methylation does not affect cleavage
+	MethylationNotSensitive MethylationSensitivity = "no"
+
+	// MethylationImpaired indicates that methylation impairs (but does not fully block) cleavage
+	MethylationImpaired MethylationSensitivity = "impaired"
+
+	// MethylationBlocked indicates that methylation blocks cleavage
+	MethylationBlocked MethylationSensitivity = "blocked"
+)
+
+// REVersion is the latest version of the *.re format; files without a `re_version' line are version 1 and have the five
+// positional columns enzyme_name, recognition_sequence, non_palindromic_cleavage, PDB_ID and isoschizomers; starting with
+// version 2, columns are identified by the labels line (starting with `enzyme_name') and unknown columns are ignored
+const REVersion = 2
+
+// reColumnsV1 holds the positional columns of version 1 *.re files
+var reColumnsV1 = []string{"enzyme_name", "recognition_sequence", "non_palindromic_cleavage", "PDB_ID", "isoschizomers"}
+
+// reColumnsV2 holds all columns that are known in version 2 *.re files (in the order they are written by `WriteEnzymesRE')
+var reColumnsV2 = append(reColumnsV1[:len(reColumnsV1):len(reColumnsV1)], "top_cut", "bottom_cut", "suppliers", "methylation_site", "buffer", "buffer_activity", "incubation_temp", "inactivation_temp", "dam", "dcm", "cpg", "star_activity")
+
+// reSetters maps *.re column labels to functions that set the respective field of a `RestrictEnzyme'
+var reSetters = map[string]func(e *RestrictEnzyme, item string) error{
+	"enzyme_name":              func(e *RestrictEnzyme, item string) error { e.Name = item; return nil },
+	"recognition_sequence":     func(e *RestrictEnzyme, item string) error { e.RecognitionSite = item; return nil },
+	"non_palindromic_cleavage": func(e *RestrictEnzyme, item string) error { e.NoPalinCleav = item; return nil },
+	"PDB_ID":                   func(e *RestrictEnzyme, item string) error { e.ID = item; return nil },
+	"isoschizomers":            func(e *RestrictEnzyme, item string) error { e.Isoschizomeres = splitRebaseList(item); return nil },
+	"top_cut": func(e *RestrictEnzyme, item string) (err error) {
+		e.TopCut, err = parseCut(item)
+		return
+	},
+	"bottom_cut": func(e *RestrictEnzyme, item string) (err error) {
+		e.BottomCut, err = parseCut(item)
+		return
+	},
+	"suppliers":        func(e *RestrictEnzyme, item string) error { e.Suppliers = splitSupplierCodes(item); return nil },
+	"methylation_site": func(e *RestrictEnzyme, item string) error { e.MethylationSite = item; return nil },
+	"buffer":           func(e *RestrictEnzyme, item string) error { e.Buffer = item; return nil },
+	"buffer_activity": func(e *RestrictEnzyme, item string) (err error) {
+		e.BufferActivity, err = ParseBufferActivity(item)
+		return
+	},
+	"incubation_temp": func(e *RestrictEnzyme, item string) (err error) {
+		e.IncubationTemp, err = parseTemperature(item)
+		return
+	},
+	"inactivation_temp": func(e *RestrictEnzyme, item string) (err error) {
+		e.InactivationTemp, err = parseTemperature(item)
+		return
+	},
+	"dam": func(e *RestrictEnzyme, item string) (err error) { e.Dam, err = parseSensitivity(item); return },
+	"dcm": func(e *RestrictEnzyme, item string) (err error) { e.Dcm, err = parseSensitivity(item); return },
+	"cpg": func(e *RestrictEnzyme, item string) (err error) { e.CpG, err = parseSensitivity(item); return },
+	"star_activity": func(e *RestrictEnzyme, item string) error {
+		switch item {
+		case "yes":
+			e.StarActivity = true
+		case "", "no":
+			e.StarActivity = false
+		default:
+			return fmt.Errorf("expected 'yes' or 'no', not %q", item)
+		}
+		return nil
+	},
 }
 
 // ParseEnzymesFromFile parses enzyme data (identifiers, recognition sequences, etc.) from
-// a *.re file (see the example in ./assets/enzymes.re) and returns a map with enzyme names as
-// keys and `restricEnzyme' structs as values
+// a *.re file (see the example in ./assets/enzymes.re, the file may be gzip-compressed) and returns
+// a map with enzyme names as keys and `restricEnzyme' structs as values; use `ParseEnzymes' to parse
+// enzyme data from an `io.Reader' in any of the supported formats
 func ParseEnzymesFromFile(file string) (map[string]RestrictEnzyme, error) {
 	// check validity of input
-	// return an error if `file' is not a *.re
-	if path.Ext(file) != ".re" {
+	// return an error if `file' is not a *.re (or *.re.gz)
+	if path.Ext(strings.TrimSuffix(file, ".gz")) != ".re" {
 		return nil, fmt.Errorf("invalid input: %v is not a *.re file (see ./doc.go for more information)", file)
 	}
 
@@ -33,30 +122,52 @@ func ParseEnzymesFromFile(file string) (map[string]RestrictEnzyme, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error opening file: %v", err)
 	}
-	defer func() {
-		err = f.Close()
-		if err != nil {
-			log.Fatalf("error closing file: %v\n", err)
-		}
-	}()
-	b, err := ioutil.ReadAll(f)
+	defer f.Close()
+	b, err := readData(f)
 	if err != nil {
 		return nil, fmt.Errorf("error reading from file: %v", err)
 	}
+	return parseEnzymes(b, file)
+}
+
+// parseEnzymes parses the contents `b' of a *.re file; `source' identifies the data in user output
+func parseEnzymes(b []byte, source string) (map[string]RestrictEnzyme, error) {
+	// find out which column holds which data item
+	columns, err := parseREHeader(b)
+	if err != nil {
+		return nil, err
+	}
 
 	// create map that will ultimately be returned
 	enzymesMap := make(map[string]RestrictEnzyme)
 
 	// parse data line-wise into a `restrictEnzyme' struct
 	var column int                    /* variable to keep track of current column in *.re file */
-	var line int                      /* variable to keep track of current line in *.re file (for user output) */
+	var line int                      /* variable to keep track of the number of data lines in *.re file (for user output) */
+	fileLine := 1                     /* variable to keep track of current line in *.re file (for error messages) */
 	var parse bool                    /* variable to keep track of whether the current line should be parsed or not */
 	var openQuote bool                /* variable to keep track of whether a certain "'" is currently open or not */
 	var dataItem []byte               /* temporary variable to keep track of current data item */
 	var itemContainer *RestrictEnzyme /* temporary variable to hold current data item before adding it to map */
+	var cutItems int                  /* variable to keep track of the number of non-empty cut position columns of the current line */
+
+	// the cut positions of an enzyme are only known if both cut position columns are filled
+	addEnzyme := func() {
+		if itemContainer == nil {
+			return
+		}
+		itemContainer.CutKnown = cutItems == 2
+		if _, ok := enzymesMap[itemContainer.Name]; !ok {
+			enzymesMap[itemContainer.Name] = *itemContainer
+		}
+	}
 
 Loop:
 	for i, n := 0, len(b); i < n; i++ {
+		if (i > 0) && (b[i-1] == '\n') {
+			fileLine++
+		}
+
 		// assume that the document is not yet fully parsed => decide what to do next
 		if i < (n - 2) {
 			// if current char is a new line delimiter, decide how to proceed
@@ -70,12 +181,9 @@ Loop:
 					line++
 					column = 0
 					parse = true
-					if itemContainer != nil {
-						if _, ok := enzymesMap[itemContainer.Name]; !ok {
-							enzymesMap[itemContainer.Name] = *itemContainer
-						}
-					}
+					addEnzyme()
 					itemContainer = new(RestrictEnzyme)
+					cutItems = 0
 
 				// next char is not a valid data item delimiter or a comment => do not parse next line
 				case (b[i+1] != '\'') || (b[i+2] == '*'):
@@ -95,17 +203,15 @@ Loop:
 			// then, increment column count and continue loop after resetting the temporary
 			// data item variable `dataItem' and set `openQuote' to false
 			if openQuote {
-				switch column {
-				case 0:
-					itemContainer.Name = string(dataItem)
-				case 1:
-					itemContainer.RecognitionSite = string(dataItem)
-				case 2:
-					itemContainer.NoPalinCleav = string(dataItem)
-				case 3:
-					itemContainer.ID = string(dataItem)
-				case 4:
-					itemContainer.Isoschizomeres = strings.Split(string(dataItem), ",")
+				if column < len(columns) {
+					if setter, ok := reSetters[columns[column]]; ok {
+						if err := setter(itemContainer, string(dataItem)); err != nil {
+							return nil, &ParseError{Source: source, Line: fileLine, Err: fmt.Errorf("invalid %s of enzyme %s: %v", columns[column], itemContainer.Name, err)}
+						}
+					}
+					if ((columns[column] == "top_cut") || (columns[column] == "bottom_cut")) && (len(dataItem) > 0) {
+						cutItems++
+					}
 				}
 				column++
 				dataItem = make([]byte, 0)
@@ -119,11 +225,7 @@ Loop:
 
 			// current char is the last char in the document => add parsed results to `enzymesMap'
 			if (i + 1) == n {
-				if itemContainer != nil {
-					if _, ok := enzymesMap[itemContainer.Name]; !ok {
-						enzymesMap[itemContainer.Name] = *itemContainer
-					}
-				}
+				addEnzyme()
 			}
 
 			// otherwise => continue the loop
@@ -138,25 +240,95 @@ Loop:
 		// current char is the last char in the document and
 		// no other condition triggered at this point => add parsed results to `enzymesMap'
 		if (i + 1) == n {
-			if itemContainer != nil {
-				if _, ok := enzymesMap[itemContainer.Name]; !ok {
-					enzymesMap[itemContainer.Name] = *itemContainer
-				}
-			}
+			addEnzyme()
 		}
 	}
 
-	fmt.Printf("parsed %d of %d enzyme(s) from '%s'\n", len(enzymesMap), line, file)
+	logf("parsed %d of %d enzyme(s) from '%s'\n", len(enzymesMap), line, source)
 	return enzymesMap, nil
 }
 
+// parseREHeader returns the column labels of a *.re file, based on its `re_version' and labels lines
+func parseREHeader(b []byte) ([]string, error) {
+	version := 1
+	var labels []string
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "re_version":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("invalid *.re version line: %q", line)
+			}
+			v, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid *.re version line: %q", line)
+			}
+			version = v
+		case "enzyme_name":
+			if labels == nil {
+				labels = fields
+			}
+		}
+	}
+
+	// version 1 files always have the same five columns, newer versions must label their columns
+	switch {
+	case version == 1:
+		return reColumnsV1, nil
+	case (version < 1) || (version > REVersion):
+		return nil, fmt.Errorf("unsupported *.re version %d (latest supported version is %d)", version, REVersion)
+	case labels == nil:
+		return nil, fmt.Errorf("*.re files of version %d require a column labels line starting with 'enzyme_name'", version)
+	}
+	return labels, nil
+}
+
+// parseTemperature parses a temperature in °C from a *.re data item (an empty item or 'no' are returned as 0)
+func parseTemperature(item string) (int, error) {
+	if (item == "") || (item == "no") {
+		return 0, nil
+	}
+	t, err := strconv.Atoi(item)
+	if err != nil {
+		return 0, fmt.Errorf("expected a temperature in °C, not %q", item)
+	}
+	return t, nil
+}
+
+// parseCut parses a cut position (see `RestrictEnzyme.TopCut') from a *.re data item (an empty item is returned as 0,
+// the cut positions are only known if both cut position columns are filled)
+func parseCut(item string) (int, error) {
+	if item == "" {
+		return 0, nil
+	}
+	c, err := strconv.Atoi(item)
+	if err != nil {
+		return 0, fmt.Errorf("expected a cut position, not %q", item)
+	}
+	return c, nil
+}
+
+// parseSensitivity parses a `MethylationSensitivity' from a *.re data item
+func parseSensitivity(item string) (MethylationSensitivity, error) {
+	switch s := MethylationSensitivity(item); s {
+	case MethylationUnknown, MethylationNotSensitive, MethylationImpaired, MethylationBlocked:
+		return s, nil
+	}
+	return MethylationUnknown, fmt.Errorf("expected one of 'no', 'impaired', 'blocked', not %q", item)
+}
+
 // ParseSequenceFromFile parses a plasmid or DNA sequence from a *.seq file (see the example
-// in ./assets/tp53.seq) and returns the sequence as a string
+// in ./assets/tp53.seq), a FASTA, GenBank, EMBL or SnapGene file (the first record is used, the file may be gzip-compressed)
+// and returns the sequence as a string; use `ParseSequence' to parse a sequence from an `io.Reader'
+// and `ParseSequenceRecordsFromFile' to get all records with their names
 func ParseSequenceFromFile(file string) (string, error) {
 	// check validity of input
-	// return an error if `file' is not a *.seq
-	if path.Ext(file) != ".seq" {
-		return "", fmt.Errorf("invalid input: %v is not a *.seq file (see ./doc.go for more information)", file)
+	// return an error if `file' is not a *.seq, FASTA, GenBank, EMBL or SnapGene file (or a gzip-compressed one)
+	if ext := path.Ext(strings.TrimSuffix(file, ".gz")); (ext != ".seq") && !sequenceExtensions[ext] {
+		return "", fmt.Errorf("invalid input: %v is not a *.seq, FASTA, GenBank, EMBL or SnapGene file (see ./doc.go for more information)", file)
 	}
 
 	// open file and read its contents
@@ -164,24 +336,32 @@ func ParseSequenceFromFile(file string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("error opening file: %v", err)
 	}
-	defer func() {
-		err = f.Close()
-		if err != nil {
-			log.Fatalf("error closing file: %v", err)
-		}
-	}()
-	b, err := ioutil.ReadAll(f)
+	defer f.Close()
+	b, err := readData(f)
 	if err != nil {
 		return "", fmt.Errorf("error reading from file: %v", err)
 	}
+	records, err := parseSequenceRecords(b, file, "")
+	if len(records) == 0 {
+		return "", err
+	}
+	return records[0].Sequence, err
+}
 
+// parseSequence parses the contents `b' of a *.seq file; `source' identifies the data in user output
+func parseSequence(b []byte, source string) (string, error) {
 	// parse data line-wise into a `restrictEnzyme' struct
 	var noNucleotides int /* variable to keep track of number of parsed nucleotides *.seq file (for user output) */
+	line := 1             /* variable to keep track of current line in *.seq file (for error messages) */
 	var parse bool        /* variable to keep track of whether the current line should be parsed or not */
 	var seq []byte        /* temporary variable to hold the growing nucleotide sequence as it is parsed */
 
 Loop:
 	for i, n := 0, len(b); i < n; i++ {
+		if (i > 0) && (b[i-1] == '\n') {
+			line++
+		}
+
 		// decide what to do next
 		if i < len(b)-2 {
 			// if current char is a new line delimiter, decide how to proceed
@@ -221,12 +401,12 @@ Loop:
 			continue Loop
 		}
 		if !IsNucleotide(b[i]) {
-			return string(seq), fmt.Errorf("invalid letter in nucleotide sequence: %s at position %d", string(b[i]), noNucleotides)
+			return string(seq), &ParseError{Source: source, Line: line, Err: &NucleotideError{Letter: rune(b[i]), Position: noNucleotides + 1}}
 		}
 		seq = append(seq, b[i])
 		noNucleotides++
 	}
 
-	fmt.Printf("parsed %d nucleotides from '%s'\n", noNucleotides, file)
+	logf("parsed %d nucleotides from '%s'\n", noNucleotides, source)
 	return string(seq), nil
 }
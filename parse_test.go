@@ -79,6 +79,51 @@ func TestParseEnzymesFromFile(t *testing.T) {
 			},
 			err: nil,
 		},
+		// test version 2 file with reordered, additional and unknown columns: `parse4.re'
+		{
+			in: "tests/parse4.re",
+			want: map[string]RestrictEnzyme{
+				"XbaI": {
+					Name:             "XbaI",
+					RecognitionSite:  "TCTAGA",
+					NoPalinCleav:     "no",
+					TopCut:           1,
+					BottomCut:        5,
+					CutKnown:         true,
+					Suppliers:        []string{"N"},
+					Buffer:           "rCutSmart",
+					BufferActivity:   map[string]int{"r2.1": 100, "r3.1": 75, "rCutSmart": 100},
+					IncubationTemp:   37,
+					InactivationTemp: 65,
+					Dam:              MethylationBlocked,
+					Dcm:              MethylationNotSensitive,
+					CpG:              MethylationNotSensitive,
+				},
+				"BamHI": {
+					Name:            "BamHI",
+					RecognitionSite: "GGATCC",
+					NoPalinCleav:    "no",
+					TopCut:          1,
+					Isoschizomeres:  []string{"BstI"},
+					Suppliers:       []string{"B", "N"},
+					IncubationTemp:  37,
+					StarActivity:    true,
+				},
+			},
+			err: nil,
+		},
+		// test unsupported version: `parse5.re'
+		{
+			in:   "tests/parse5.re",
+			want: nil,
+			err:  errors.New("unsupported *.re version 99 (latest supported version is 2)"),
+		},
+		// test invalid data item in a version 2 file: `parse6.re'
+		{
+			in:   "tests/parse6.re",
+			want: nil,
+			err:  errors.New("tests/parse6.re:6: invalid dam of enzyme XbaI: expected one of 'no', 'impaired', 'blocked', not \"maybe\""),
+		},
 	}
 
 	// loop over test cases
@@ -115,7 +160,7 @@ func TestParseSequenceFromFile(t *testing.T) {
 		{
 			in:   "tests/parse1.re",
 			want: "",
-			err:  errors.New("invalid input: tests/parse1.re is not a *.seq file (see ./doc.go for more information)"),
+			err:  errors.New("invalid input: tests/parse1.re is not a *.seq, FASTA, GenBank, EMBL or SnapGene file (see ./doc.go for more information)"),
 		},
 		// test non-existing file
 		{
@@ -145,7 +190,7 @@ func TestParseSequenceFromFile(t *testing.T) {
 		{
 			in:   "tests/parse4.seq",
 			want: "ATTATGA",
-			err:  errors.New("invalid letter in nucleotide sequence: Q at position 7"),
+			err:  errors.New("tests/parse4.seq:7: invalid input Q at position 8, expected sequence of lower or upper case A,T,C,G"),
 		},
 	}
 
@@ -184,7 +229,7 @@ func isSimilarMap(m1, m2 map[string]RestrictEnzyme) bool {
 	if (m1 == nil) && (m2 == nil) {
 		return true
 	}
-	if (m1 == nil) || (m2 == nil) {
+	if (m1 == nil) || (m2 == nil) || (len(m1) != len(m2)) {
 		return false
 	}
 	for k, v := range m1 {
@@ -199,14 +244,31 @@ func isSimilarMap(m1, m2 map[string]RestrictEnzyme) bool {
 			return false
 		} else if val.ID != v.ID {
 			return false
-		} else if len(val.Isoschizomeres) != len(v.Isoschizomeres) {
+		} else if val.TopCut != v.TopCut || val.BottomCut != v.BottomCut || val.CutKnown != v.CutKnown {
+			return false
+		} else if val.MethylationSite != v.MethylationSite || val.Buffer != v.Buffer || val.StarActivity != v.StarActivity {
+			return false
+		} else if val.IncubationTemp != v.IncubationTemp || val.InactivationTemp != v.InactivationTemp {
+			return false
+		} else if val.Dam != v.Dam || val.Dcm != v.Dcm || val.CpG != v.CpG {
+			return false
+		} else if FormatBufferActivity(val.BufferActivity) != FormatBufferActivity(v.BufferActivity) {
+			return false
+		} else if !isSimilarSlice(val.Isoschizomeres, v.Isoschizomeres) || !isSimilarSlice(val.Suppliers, v.Suppliers) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSimilarSlice returns true if `s1' and `s2' hold the same strings in the same order
+func isSimilarSlice(s1, s2 []string) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := 0; i < len(s1); i++ {
+		if s1[i] != s2[i] {
 			return false
-		} else {
-			for i := 0; i < len(val.Isoschizomeres); i++ {
-				if val.Isoschizomeres[i] != v.Isoschizomeres[i] {
-					return false
-				}
-			}
 		}
 	}
 	return true
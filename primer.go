@@ -3,8 +3,8 @@ package cloningprimer
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -16,6 +16,11 @@ const (
 
 	// MaximumPrimerLength gives the maximum length that a primer can be
 	MaximumPrimerLength = 30
+
+	// MaximumLongPrimerLength gives the maximum length of primers with long 5' tails (e.g. the homology arms of
+	// recombineering primers), whose complementary part is still limited to `MaximumPrimerLength'; longer primers
+	// exceed the limit of standard oligo synthesis
+	MaximumLongPrimerLength = 120
 )
 
 // FindForward finds a forward primer with a `length' number of complementary nucleotides, binding to the specified starting position (`seqStart'), counting from the 5' end) and up to (`seqStart' + `length' - 1); e.g. if `length' = 10 and `start' = 1, a primer will be returned that binds to nucleotides 1 - 10; the boolean `startCodon' indicates if an 'ATG' should be added and is only evaluated if no 'ATG' is found in the input `seq' (if that is the case, 'ATG' adds three nucleotides to the total length of the primer); `random' indicates how many random nucleotides should be added as an overhang; `restrict' is a string giving the recognition sequence of a restriction enzyme
@@ -26,10 +31,11 @@ func FindForward(seq, restrict string, seqStart, length, random int, startCodon
 		return "", fmt.Errorf("invalid input: primer start point must be an integer > 0 (not %d)", seqStart)
 	}
 
-	// return an error if `seq' contains invalid letters (anything except for A,T,C,G)
+	// return an error if `seq' contains invalid letters (anything except for A,T,C,G and N, which marks unknown
+	// nucleotides of genomic records and is only rejected in the region that the primer binds to)
 	for i := 0; i < len(seq); i++ {
-		if !IsNucleotide(seq[i]) {
-			return "", fmt.Errorf("invalid input %s at position %d, expected sequence of lower or upper case A,T,C,G", string(seq[i]), i+1)
+		if !IsNucleotide(seq[i]) && (seq[i] != 'N') && (seq[i] != 'n') {
+			return "", &NucleotideError{Letter: rune(seq[i]), Position: i + 1}
 		}
 	}
 
@@ -52,6 +58,9 @@ func FindForward(seq, restrict string, seqStart, length, random int, startCodon
 	var b []byte
 	for i, l := range []byte(seq) {
 		if (i >= (seqStart - 1)) && !(i >= (seqStart + length - 1)) {
+			if !IsNucleotide(l) { /* the primer cannot bind to unknown nucleotides */
+				return "", &NucleotideError{Letter: rune(l), Position: i + 1}
+			}
 			l := []byte(strings.ToUpper(string(l))) /* make current letter a string, upper case, and byte again */
 			b = append(b, l...)
 		}
@@ -82,10 +91,11 @@ func FindReverse(seq, restrict string, seqStart, length, random int, stopCodon b
 		return "", fmt.Errorf("invalid input: primer start point must be an integer > 0 (not %d)", seqStart)
 	}
 
-	// return an error if `seq' contains invalid letters (anything except for A,T,C,G)
+	// return an error if `seq' contains invalid letters (anything except for A,T,C,G and N, which marks unknown
+	// nucleotides of genomic records and is only rejected in the region that the primer binds to)
 	for i := 0; i < len(seq); i++ {
-		if !IsNucleotide(seq[i]) {
-			return "", fmt.Errorf("invalid input %s at position %d, expected sequence of lower or upper case A,T,C,G", string(seq[i]), i+1)
+		if !IsNucleotide(seq[i]) && (seq[i] != 'N') && (seq[i] != 'n') {
+			return "", &NucleotideError{Letter: rune(seq[i]), Position: i + 1}
 		}
 	}
 
@@ -104,27 +114,22 @@ func FindReverse(seq, restrict string, seqStart, length, random int, stopCodon b
 		return "", fmt.Errorf("invalid input, the given sequence (%d nucleotides) is not long enough for a primer of length = %d starting at nucleotide %d (%d > %d)", len(seq), length, seqStart, seqStart+length-1, len(seq))
 	}
 
-	// compute the reverse of the input sequence and the complementary sequence of the reversed sequence `seqRev'
-	seqRev := Reverse(seq)
-	var complement []byte
-	for i := 0; i < len(seq); i++ {
-		c, err := Complement([]byte(seqRev)[i])
-		if err != nil {
-			log.Fatalf("cannot compute complement of %v: %v\n", []byte(seqRev)[i], err)
+	// compute the reverse of the region that the primer binds to and append its complementary nucleotides to a slice of
+	// bytes (the primer cannot bind to unknown nucleotides)
+	first := len(seq) - seqStart - length + 1 /* index of the first nucleotide of the region in `seq' */
+	for i := first; i < first+length; i++ {
+		if !IsNucleotide(seq[i]) {
+			return "", &NucleotideError{Letter: rune(seq[i]), Position: i + 1}
 		}
-		complement = append(complement, c)
 	}
-
-	// loop over letters in `complement' sequence and append the appropriate ones to a slice of bytes
+	region := Reverse(seq[first : first+length])
 	var b []byte
-	for i, l := range complement {
-		if (i >= (seqStart - 1)) && !(i >= (seqStart + length - 1)) {
-			l := []byte(strings.ToUpper(string(l))) /* make current letter a string, upper case, and byte again */
-			b = append(b, l...)
-		}
-		if i >= (seqStart + length - 1) {
-			break
+	for i := 0; i < len(region); i++ {
+		c, err := Complement(region[i])
+		if err != nil {
+			return "", fmt.Errorf("cannot compute complement of %v: %v", string(region[i]), err)
 		}
+		b = append(b, c)
 	}
 
 	// if the selected part of `seq' does not have a start codon, check how to proceed
@@ -152,6 +157,13 @@ func IsNucleotide(letter byte) bool {
 	}
 }
 
+// IsIUPACNucleotide returns true if `letter' is a valid nucleotide or an IUPAC code for ambiguous nucleotides (e.g. N
+// or R); lower case letters are accepted
+func IsIUPACNucleotide(letter byte) bool {
+	_, ok := iupacCodes[byte(unicode.ToUpper(rune(letter)))]
+	return ok
+}
+
 // Reverse finds the reverse of a nucleotide sequence; it requires prior checking of possible sources of errors (for example, it does not check if the input sequence contains invalid nucleotide letters); thus, `Reverse' should be called in the context of a valid `seq' input argument
 func Reverse(seq string) string {
 	var seqRev []byte
@@ -348,3 +360,15 @@ func ValidateSequence(seq []byte) (string, error) {
 	}
 	return string(s), nil
 }
+
+// PCRProduct returns the sequence of the PCR product that is amplified from `seq' with a `forward' and a `reverse' primer,
+// which were computed by `FindForward' and `FindReverse' with the start positions `startF' and `startR' and the
+// complementary lengths `lengthF' and `lengthR'; the product includes all overhangs, restriction sites and codons of the primers
+func PCRProduct(seq, forward, reverse string, startF, lengthF, startR, lengthR int) (string, error) {
+	inner := startF - 1 + lengthF            /* first nucleotide after the forward primer binding site */
+	end := len(seq) - (startR - 1) - lengthR /* first nucleotide of the reverse primer binding site */
+	if (startF < 1) || (startR < 1) || (lengthF > len(forward)) || (lengthR > len(reverse)) || (inner > end) {
+		return "", fmt.Errorf("invalid input: the primer binding sites overlap or do not fit the given sequence (%d nucleotides)", len(seq))
+	}
+	return strings.ToUpper(forward) + strings.ToUpper(seq[inner:end]) + reverseComplementIUPAC(strings.ToUpper(reverse)), nil
+}
@@ -70,6 +70,31 @@ func TestFindForward(t *testing.T) {
 			want: "",
 			err:  errors.New("invalid input V at position 7, expected sequence of lower or upper case A,T,C,G"),
 		},
+		// test `seq' with unknown nucleotides (N) outside of and inside the region that the primer binds to
+		{
+			in: inputForPrimer{
+				seq:      "ATGCCGTCGCATTCTGNNnn",
+				restrict: "GAATTC",
+				seqStart: 1,
+				length:   16,
+				random:   4,
+				addCodon: true,
+			},
+			want: "AGCTGAATTCATGCCGTCGCATTCTG",
+			err:  nil,
+		},
+		{
+			in: inputForPrimer{
+				seq:      "ATGCCGTCGCATTCTGNNnn",
+				restrict: "GAATTC",
+				seqStart: 5,
+				length:   16,
+				random:   4,
+				addCodon: true,
+			},
+			want: "",
+			err:  errors.New("invalid input N at position 17, expected sequence of lower or upper case A,T,C,G"),
+		},
 		// test `seq' that is exactly of length (`length' + `seqStart' - 1)
 		{
 			in: inputForPrimer{
@@ -257,6 +282,31 @@ func TestFindReverse(t *testing.T) {
 			want: "",
 			err:  errors.New("invalid input V at position 7, expected sequence of lower or upper case A,T,C,G"),
 		},
+		// test `seq' with unknown nucleotides (N) outside of and inside the region that the primer binds to
+		{
+			in: inputForPrimer{
+				seq:      "nnNNATGCCGTCGCATTCTG",
+				restrict: "GAATTC",
+				seqStart: 1,
+				length:   16,
+				random:   4,
+				addCodon: true,
+			},
+			want: "AGCTGAATTCTTACAGAATGCGACGGCAT",
+			err:  nil,
+		},
+		{
+			in: inputForPrimer{
+				seq:      "nnNNATGCCGTCGCATTCTG",
+				restrict: "GAATTC",
+				seqStart: 5,
+				length:   16,
+				random:   4,
+				addCodon: true,
+			},
+			want: "",
+			err:  errors.New("invalid input n at position 1, expected sequence of lower or upper case A,T,C,G"),
+		},
 		// test `seq' that is exactly of length (`length' + `seqStart' - 1)
 		{
 			in: inputForPrimer{
@@ -688,3 +738,59 @@ func TestAddOverhang(t *testing.T) {
 func TestValidateSequence(t *testing.T) {
 	// TODO: implement unit tests
 }
+
+type testCaseProduct struct {
+	seq              string
+	startF, lengthF  int
+	startR, lengthR  int
+	forward, reverse string
+	want             string
+	err              error
+}
+
+func TestPCRProduct(t *testing.T) {
+	cases := []testCaseProduct{
+		// primers with overhangs, restriction sites and codons that are not part of the template
+		{
+			seq:    "ATGAAACCCGGGTTTAAAGGGCCCAAATTTGGGCCCTAA",
+			startF: 1, lengthF: 12, startR: 1, lengthR: 12,
+			forward: "AGCTGGATCCATGAAACCCGGG",
+			reverse: "AGCTGAATTCTTAGGGCCCAAA",
+			want:    "AGCTGGATCCATGAAACCCGGGTTTAAAGGGCCCAAATTTGGGCCCTAAGAATTCAGCT",
+			err:     nil,
+		},
+		// sub-region of the template
+		{
+			seq:    "CCCATGAAACCCGGGTTTAAACCC",
+			startF: 4, lengthF: 8, startR: 4, lengthR: 8,
+			forward: "GGATCCATGAAACC",
+			reverse: "GAATTCTTTAAACC",
+			want:    "GGATCCATGAAACCCGGGTTTAAAGAATTC",
+			err:     nil,
+		},
+		// overlapping binding sites
+		{
+			seq:    "ATGAAACCCGGGTTTAA",
+			startF: 1, lengthF: 10, startR: 1, lengthR: 10,
+			forward: "ATGAAACCCG",
+			reverse: "TTAAACCCGG",
+			want:    "",
+			err:     errors.New("invalid input: the primer binding sites overlap or do not fit the given sequence (17 nucleotides)"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := PCRProduct(c.seq, c.forward, c.reverse, c.startF, c.lengthF, c.startR, c.lengthR)
+
+		// test similarity of expected and received value
+		if got != c.want {
+			t.Errorf("PCRProduct(%v) == %v, want %v\n", c.seq, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("PCRProduct(%v) == %v, want %v\n", c.seq, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package cloningprimer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+const (
+	// FormatRE identifies the *.re enzyme format (see ./app/assets/enzymes.re)
+	FormatRE = "re"
+
+	// FormatSeq identifies the *.seq sequence format (see ./app/assets/tp53.seq)
+	FormatSeq = "seq"
+)
+
+// gzipMagic holds the first two bytes of every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ParseEnzymes parses restriction enzymes from `r' and returns a map with enzyme names as keys and `RestrictEnzyme'
+// structs as values; the format of the data (`FormatRE' or one of the REBASE formats) is detected from its contents
+// and gzip-compressed data is decompressed transparently (emboss data does not include the emboss_r references)
+func ParseEnzymes(r io.Reader) (map[string]RestrictEnzyme, error) {
+	b, err := readData(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format := DetectFormat(b); format {
+	case FormatRE:
+		return parseEnzymes(b, "input")
+	case RebaseWithrefm:
+		return parseWithrefm(b, "input")
+	case RebaseBairoch:
+		return parseBairoch(b, "input")
+	case RebaseEmboss:
+		return parseEmboss(b, "input")
+	case "":
+		return nil, fmt.Errorf("invalid input: unknown enzyme data format")
+	default:
+		return nil, fmt.Errorf("invalid input: expected enzyme data, found %s data", format)
+	}
+}
+
+// ParseSequence parses a plasmid or DNA sequence in *.seq, FASTA, GenBank, EMBL or SnapGene format from `r' and returns the sequence as a string
+// (the first record of FASTA data, see `ParseSequenceRecords' for all records); gzip-compressed data is decompressed transparently
+func ParseSequence(r io.Reader) (string, error) {
+	b, err := readData(r)
+	if err != nil {
+		return "", err
+	}
+	records, err := parseSequenceRecords(b, "input", "")
+	if len(records) == 0 {
+		return "", err
+	}
+	return records[0].Sequence, err
+}
+
+// ParseFeatureRecords parses the records of annotated sequence data in `r' (`FormatGenBank', `FormatEMBL' or `FormatSnapGene') together
+// with their features; gzip-compressed data is decompressed transparently
+func ParseFeatureRecords(r io.Reader) ([]GenBankRecord, error) {
+	b, err := readData(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeatureRecords(b, "input", "")
+}
+
+// ParseFeatureRecordsFromFile parses the records of a (possibly gzip-compressed) GenBank, EMBL or SnapGene file together with
+// their features; the record of a SnapGene file is named after the file
+func ParseFeatureRecordsFromFile(file string) ([]GenBankRecord, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	base := path.Base(strings.TrimSuffix(file, ".gz"))
+	return parseFeatureRecords(b, file, strings.TrimSuffix(base, path.Ext(base)))
+}
+
+// parseFeatureRecords parses GenBank, EMBL or SnapGene data `b'; `source' identifies the data in errors and `name' is the
+// name of the record in SnapGene data
+func parseFeatureRecords(b []byte, source, name string) ([]GenBankRecord, error) {
+	switch format := DetectFormat(b); format {
+	case FormatGenBank:
+		return parseGenBank(b, source)
+	case FormatEMBL:
+		return parseEMBL(b, source)
+	case FormatSnapGene:
+		r, err := parseSnapGene(b, source)
+		if err != nil {
+			return nil, err
+		}
+		r.Name = name
+		return []GenBankRecord{r}, nil
+	case "":
+		return nil, fmt.Errorf("invalid input: unknown format of %s", source)
+	default:
+		return nil, fmt.Errorf("invalid input: expected GenBank, EMBL or SnapGene data in %s, found %s data", source, format)
+	}
+}
+
+// DetectFormat returns the format of `b' (`FormatRE', `FormatSeq', `FormatFasta', `FormatGenBank', `FormatEMBL', `FormatSnapGene',
+// `RebaseWithrefm', `RebaseBairoch' or `RebaseEmboss') based on its contents or an empty string if the format is unknown; gzip-compressed data must be decompressed first
+func DetectFormat(b []byte) string {
+	if isSnapGene(b) {
+		return FormatSnapGene
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var comment bool /* variable to keep track of whether the current line is part of a C-style comment */
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(text, "/*"):
+			comment = !strings.Contains(text, "*/")
+			continue
+		case comment:
+			comment = !strings.Contains(text, "*/")
+			continue
+		case (text == "") || strings.HasPrefix(text, "//"):
+			continue
+		}
+		lines = append(lines, text)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	// check the formats with unambiguous markers first
+	for _, text := range lines {
+		switch {
+		case strings.HasPrefix(text, "'"), strings.HasPrefix(text, "re_version"), strings.HasPrefix(text, "enzyme_name"):
+			return FormatRE
+		case strings.HasPrefix(text, ">"):
+			return FormatFasta
+		case strings.HasPrefix(text, "LOCUS "):
+			return FormatGenBank
+		case isEMBLHeader(text):
+			return FormatEMBL
+		case strings.HasPrefix(text, "<1>"):
+			return RebaseWithrefm
+		case strings.HasPrefix(text, "ID   "), strings.HasPrefix(text, "RS   "):
+			return RebaseBairoch
+		}
+	}
+
+	// emboss_e data holds lines with nine fields (ignoring '#' comments), *.seq data only holds nucleotides
+	emboss, seq := true, true
+	for _, text := range lines {
+		if strings.HasPrefix(text, "#") {
+			seq = false
+			continue
+		}
+		emboss = emboss && (len(strings.Fields(text)) == 9)
+		for i := 0; seq && (i < len(text)); i++ {
+			seq = IsNucleotide(text[i]) || (text[i] == ' ') || (text[i] == '\t')
+		}
+	}
+	switch {
+	case seq:
+		return FormatSeq
+	case emboss:
+		return RebaseEmboss
+	}
+	return ""
+}
+
+// readData reads all data from `r' and decompresses it if it is a gzip stream
+func readData(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(gzipMagic)); (err == nil) && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("error reading gzip data: %v", err)
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %v", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,146 @@
+package cloningprimer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type testCaseFormat struct {
+	in   string
+	want string
+}
+
+type testCaseReader struct {
+	in   string
+	gzip bool
+	want string
+	err  error
+}
+
+func TestDetectFormat(t *testing.T) {
+	cases := []testCaseFormat{
+		{in: "tests/parse1.re", want: FormatRE},
+		{in: "tests/parse4.re", want: FormatRE},
+		{in: "tests/parse1.seq", want: FormatSeq},
+		{in: "tests/parse2.seq", want: FormatSeq},
+		{in: "tests/parse3.seq", want: FormatSeq},
+		{in: "tests/parse4.seq", want: ""},
+		{in: "tests/fasta1.fa", want: FormatFasta},
+		{in: "tests/genbank1.gb", want: FormatGenBank},
+		{in: "tests/embl1.embl", want: FormatEMBL},
+		{in: "tests/snapgene1.dna", want: FormatSnapGene},
+		{in: "tests/withrefm.901", want: RebaseWithrefm},
+		{in: "tests/bairoch.901", want: RebaseBairoch},
+		{in: "tests/emboss_e.901", want: RebaseEmboss},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		b, err := ioutil.ReadFile(c.in)
+		if err != nil {
+			t.Fatalf("error reading %s: %v\n", c.in, err)
+		}
+		if got := DetectFormat(b); got != c.want {
+			t.Errorf("DetectFormat(%v) == %q, want %q\n", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseSequence(t *testing.T) {
+	cases := []testCaseReader{
+		{
+			in:   "/* a comment */\nATGG\nCCGCGT\n",
+			want: "ATGGCCGCGT",
+			err:  nil,
+		},
+		// gzip-compressed input is decompressed transparently
+		{
+			in:   "ATGGCCGCGT",
+			gzip: true,
+			want: "ATGGCCGCGT",
+			err:  nil,
+		},
+		{
+			in:   "ATTATGAQ",
+			want: "ATTATGA",
+			err:  errors.New("input:1: invalid input Q at position 8, expected sequence of lower or upper case A,T,C,G"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseSequence(bytes.NewReader(testData(t, c.in, c.gzip)))
+
+		// test similarity of expected and received value
+		if got != c.want {
+			t.Errorf("ParseSequence(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseSequence(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
+
+func TestParseEnzymes(t *testing.T) {
+	cases := []testCaseReader{
+		{in: "tests/parse4.re", want: "tests/parse4.re"},
+		{in: "tests/parse4.re", gzip: true, want: "tests/parse4.re"},
+		{in: "tests/withrefm.901", want: "tests/withrefm.901"},
+		{in: "tests/bairoch.901", gzip: true, want: "tests/bairoch.901"},
+		{in: "tests/emboss_e.901", want: "tests/emboss_e.901"},
+		{in: "tests/parse1.seq", err: errors.New("invalid input: expected enzyme data, found seq data")},
+		{in: "tests/parse4.seq", err: errors.New("invalid input: unknown enzyme data format")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		b, err := ioutil.ReadFile(c.in)
+		if err != nil {
+			t.Fatalf("error reading %s: %v\n", c.in, err)
+		}
+		got, err := ParseEnzymes(bytes.NewReader(testData(t, string(b), c.gzip)))
+
+		// the reader-based parser must return the same enzymes as the path-based parsers
+		var want map[string]RestrictEnzyme
+		switch {
+		case strings.HasSuffix(c.want, ".re"):
+			want, _ = ParseEnzymesFromFile(c.want)
+		case strings.Contains(c.want, "withrefm"):
+			want, _ = ParseWithrefmFromFile(c.want)
+		case strings.Contains(c.want, "bairoch"):
+			want, _ = ParseBairochFromFile(c.want)
+		case strings.Contains(c.want, "emboss"):
+			want, _ = ParseEmbossFromFiles(c.want, "")
+		}
+		if !isSimilarMap(got, want) {
+			t.Errorf("ParseEnzymes(%v) == %v, want %v\n", c.in, got, want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseEnzymes(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
+
+// testData returns `s' as bytes, gzip-compressed if `compress' is true
+func testData(t *testing.T, s string, compress bool) []byte {
+	if !compress {
+		return []byte(s)
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(s)); err != nil {
+		t.Fatalf("error compressing test data: %v\n", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("error compressing test data: %v\n", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,456 @@
+package cloningprimer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RebaseWithrefm identifies the REBASE "withrefm" distribution format (format #31)
+	RebaseWithrefm = "withrefm"
+
+	// RebaseBairoch identifies the REBASE "bairoch" distribution format (format #19)
+	RebaseBairoch = "bairoch"
+
+	// RebaseEmboss identifies the REBASE "emboss" distribution format (emboss_e, emboss_r, and emboss_s files)
+	RebaseEmboss = "emboss"
+)
+
+// rebaseSiteRegexp matches recognition sites in REBASE notation, e.g. GGTCTC(1/5) or (8/13)GACNNNNNNTGG(12/7)
+var rebaseSiteRegexp = regexp.MustCompile(`^(?:\((-?\d+)/(-?\d+)\))?([A-Za-z]+)(?:\((-?\d+)/(-?\d+)\))?$`)
+
+// ParseRebaseFromFile parses a local copy of the REBASE database in one of the supported distribution
+// formats (`RebaseWithrefm', `RebaseBairoch' or `RebaseEmboss') and returns a map with enzyme names as keys
+// and `RestrictEnzyme' structs as values; for the emboss format, `file' is the path to the emboss_e.### file and
+// an emboss_r.### file in the same directory is used (if it exists) to add isoschizomers, suppliers and methylation sites;
+// REBASE only reports the site that the cognate methyltransferase of an enzyme modifies (`MethylationSite'), not whether
+// the enzyme is blocked by Dam, Dcm or CpG methylation, so `Dam', `Dcm' and `CpG' are left `MethylationUnknown'
+func ParseRebaseFromFile(file, format string) (map[string]RestrictEnzyme, error) {
+	switch format {
+	case RebaseWithrefm:
+		return ParseWithrefmFromFile(file)
+	case RebaseBairoch:
+		return ParseBairochFromFile(file)
+	case RebaseEmboss:
+		refFile := filepath.Join(filepath.Dir(file), strings.Replace(filepath.Base(file), "emboss_e", "emboss_r", 1))
+		if _, err := os.Stat(refFile); (err != nil) || (refFile == file) {
+			refFile = ""
+		}
+		return ParseEmbossFromFiles(file, refFile)
+	}
+	return nil, fmt.Errorf("invalid input: unknown REBASE format %q (must be one of %s, %s, %s)", format, RebaseWithrefm, RebaseBairoch, RebaseEmboss)
+}
+
+// ParseWithrefmFromFile parses restriction enzymes from a REBASE file in "withrefm" format (fields <1> to <8>,
+// one enzyme per block); the cut positions in the recognition site field (e.g. G^AATTC or GGTCTC(1/5)) are
+// translated into `TopCut', `BottomCut' and the `NoPalinCleav' notation that is used by *.re files
+func ParseWithrefmFromFile(file string) (map[string]RestrictEnzyme, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return parseWithrefm(b, file)
+}
+
+// parseWithrefm parses the contents `b' of a REBASE file in "withrefm" format; `source' identifies the data in errors
+func parseWithrefm(b []byte, source string) (map[string]RestrictEnzyme, error) {
+	// collect the fields of a single enzyme record and convert them once the next record starts
+	enzymesMap := make(map[string]RestrictEnzyme)
+	fields := make(map[string]string)
+	var line int /* variable to keep track of the current line (for error messages) */
+	addRecord := func() error {
+		defer func() { fields = make(map[string]string) }()
+		name := fields["1"]
+		if name == "" {
+			return nil
+		}
+		enzyme, err := enzymeFromRebaseSite(name, fields["3"])
+		if err != nil {
+			return &ParseError{Source: source, Line: line, Err: err}
+		}
+		if enzyme.RecognitionSite == "" {
+			return nil /* enzymes with unknown recognition sites cannot be used for cloning */
+		}
+		enzyme.Isoschizomeres = splitRebaseList(fields["2"])
+		enzyme.MethylationSite = fields["4"]
+		enzyme.Suppliers = splitSupplierCodes(fields["7"])
+		if _, ok := enzymesMap[enzyme.Name]; !ok {
+			enzymesMap[enzyme.Name] = enzyme
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if len(text) < 3 || text[0] != '<' || text[2] != '>' {
+			continue
+		}
+		key := text[1:2]
+		if key == "1" {
+			if err := addRecord(); err != nil {
+				return nil, err
+			}
+		}
+		fields[key] = strings.TrimSpace(text[3:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading from file: %v", err)
+	}
+	if err := addRecord(); err != nil {
+		return nil, err
+	}
+	return enzymesMap, nil
+}
+
+// ParseBairochFromFile parses restriction enzymes from a REBASE file in "bairoch" format (two letter line codes
+// like ID, PT, RS, MS and CR, records are terminated by "//")
+func ParseBairochFromFile(file string) (map[string]RestrictEnzyme, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return parseBairoch(b, file)
+}
+
+// parseBairoch parses the contents `b' of a REBASE file in "bairoch" format; `source' identifies the data in errors
+func parseBairoch(b []byte, source string) (map[string]RestrictEnzyme, error) {
+	enzymesMap := make(map[string]RestrictEnzyme)
+	var line int                      /* variable to keep track of the current line (for error messages) */
+	var site string                   /* the RS line of the current record */
+	var itemContainer *RestrictEnzyme /* temporary variable to hold current record before adding it to map */
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, "//") {
+			if (itemContainer != nil) && (site != "") {
+				if err := applyBairochSite(itemContainer, site); err != nil {
+					return nil, &ParseError{Source: source, Line: line, Err: err}
+				}
+				if _, ok := enzymesMap[itemContainer.Name]; !ok && (itemContainer.RecognitionSite != "") {
+					enzymesMap[itemContainer.Name] = *itemContainer
+				}
+			}
+			itemContainer = nil
+			site = ""
+			continue
+		}
+		if len(text) < 2 {
+			continue
+		}
+		code, value := text[:2], ""
+		if len(text) > 5 {
+			value = strings.TrimSpace(text[5:])
+		}
+		if code == "ID" {
+			itemContainer = &RestrictEnzyme{Name: value}
+			continue
+		}
+		if itemContainer == nil {
+			continue
+		}
+		switch code {
+		case "PT":
+			itemContainer.Isoschizomeres = append(itemContainer.Isoschizomeres, splitRebaseList(value)...)
+		case "RS":
+			site += value
+		case "MS":
+			itemContainer.MethylationSite = strings.TrimSuffix(value, ";")
+		case "CR":
+			itemContainer.Suppliers = append(itemContainer.Suppliers, splitSupplierCodes(value)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading from file: %v", err)
+	}
+	return enzymesMap, nil
+}
+
+// ParseEmbossFromFiles parses restriction enzymes from a REBASE emboss_e.### file (recognition sites and cut
+// positions) and, if `refFile' is not empty, adds isoschizomers, methylation sites and supplier data from the
+// corresponding emboss_r.### file
+func ParseEmbossFromFiles(file, refFile string) (map[string]RestrictEnzyme, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	enzymesMap, err := parseEmboss(b, file)
+	if (err != nil) || (refFile == "") {
+		return enzymesMap, err
+	}
+	b, err = readFile(refFile)
+	if err != nil {
+		return nil, err
+	}
+	addEmbossReferences(enzymesMap, b)
+	return enzymesMap, nil
+}
+
+// parseEmboss parses the contents `b' of a REBASE emboss_e.### file; `source' identifies the data in errors
+func parseEmboss(b []byte, source string) (map[string]RestrictEnzyme, error) {
+	var err error
+
+	// every non-comment line holds: name, pattern, length, number of cuts, blunt, c1, c2, c3, c4
+	enzymesMap := make(map[string]RestrictEnzyme)
+	for i, text := range strings.Split(string(b), "\n") {
+		text = strings.TrimSpace(text)
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		f := strings.Fields(text)
+		if len(f) != 9 {
+			return nil, &ParseError{Source: source, Line: i + 1, Err: fmt.Errorf("expected 9 fields, found %d", len(f))}
+		}
+		var nums [7]int
+		for j := range nums {
+			nums[j], err = strconv.Atoi(f[j+2])
+			if err != nil {
+				return nil, &ParseError{Source: source, Line: i + 1, Err: err}
+			}
+		}
+		enzyme := RestrictEnzyme{
+			Name:            f[0],
+			RecognitionSite: strings.ToUpper(f[1]),
+			NoPalinCleav:    "no",
+		}
+		switch nums[1] {
+		case 2:
+			enzyme.TopCut, enzyme.BottomCut, enzyme.CutKnown = embossCut(nums[3]), embossCut(nums[4]), true
+			enzyme.NoPalinCleav = cleavageNotation(enzyme.RecognitionSite, enzyme.TopCut, enzyme.BottomCut)
+		case 4:
+			enzyme.TopCut, enzyme.BottomCut, enzyme.CutKnown = embossCut(nums[3]), embossCut(nums[4]), true
+			n := len(enzyme.RecognitionSite)
+			enzyme.NoPalinCleav = fmt.Sprintf("(%d/%d)(%d/%d)", -enzyme.TopCut, -enzyme.BottomCut, embossCut(nums[5])-n, embossCut(nums[6])-n)
+		}
+		if _, ok := enzymesMap[enzyme.Name]; !ok {
+			enzymesMap[enzyme.Name] = enzyme
+		}
+	}
+	return enzymesMap, nil
+}
+
+// addEmbossReferences adds the reference data in the contents `b' of a REBASE emboss_r.### file to `enzymesMap'
+func addEmbossReferences(enzymesMap map[string]RestrictEnzyme, b []byte) {
+	// reference data: name, organism, isoschizomers, methylation site, source, suppliers, number of references, references, "//"
+	var record []string
+	for _, text := range strings.Split(string(b), "\n") {
+		text = strings.TrimRight(text, "\r")
+		if (len(record) == 0) && (strings.HasPrefix(text, "#") || (strings.TrimSpace(text) == "")) {
+			continue
+		}
+		if text != "//" {
+			record = append(record, text)
+			continue
+		}
+		if len(record) >= 6 {
+			if enzyme, ok := enzymesMap[strings.TrimSpace(record[0])]; ok {
+				enzyme.Isoschizomeres = splitRebaseList(record[2])
+				enzyme.MethylationSite = strings.TrimSpace(record[3])
+				enzyme.Suppliers = splitSupplierCodes(record[5])
+				enzymesMap[enzyme.Name] = enzyme
+			}
+		}
+		record = nil
+	}
+}
+
+// ParseRebaseSuppliersFromFile parses a REBASE emboss_s.### file and returns a map with the single letter
+// supplier codes that are used in `RestrictEnzyme.Suppliers' as keys and supplier names as values
+func ParseRebaseSuppliersFromFile(file string) (map[string]string, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	suppliers := make(map[string]string)
+	for _, text := range strings.Split(string(b), "\n") {
+		text = strings.TrimSpace(text)
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		f := strings.Fields(text)
+		if (len(f) < 2) || (len(f[0]) != 1) {
+			continue
+		}
+		suppliers[f[0]] = strings.Join(f[1:], " ")
+	}
+	return suppliers, nil
+}
+
+// readFile opens a (possibly gzip-compressed) file and returns its contents
+func readFile(file string) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %v", err)
+	}
+	defer f.Close()
+	b, err := readData(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading from file: %v", err)
+	}
+	return b, nil
+}
+
+// enzymeFromRebaseSite creates a `RestrictEnzyme' from a recognition site in REBASE notation; a site of "?"
+// results in an enzyme without `RecognitionSite'
+func enzymeFromRebaseSite(name, site string) (RestrictEnzyme, error) {
+	enzyme := RestrictEnzyme{Name: name, NoPalinCleav: "no"}
+	if (site == "") || (site == "?") {
+		return enzyme, nil
+	}
+
+	// cut within the recognition site, e.g. G^AATTC
+	if i := strings.Index(site, "^"); i >= 0 {
+		enzyme.RecognitionSite = strings.ToUpper(strings.Replace(site, "^", "", 1))
+		enzyme.TopCut = i
+		enzyme.BottomCut = len(enzyme.RecognitionSite) - i
+		enzyme.CutKnown = true
+		return enzyme, nil
+	}
+
+	// cut outside of the recognition site, e.g. GGTCTC(1/5) or (8/13)GACNNNNNNTGG(12/7), or no cut information at all
+	m := rebaseSiteRegexp.FindStringSubmatch(site)
+	if m == nil {
+		return enzyme, fmt.Errorf("invalid recognition site %q for enzyme %s", site, name)
+	}
+	enzyme.RecognitionSite = strings.ToUpper(m[3])
+	if (m[1] == "") && (m[4] == "") {
+		return enzyme, nil
+	}
+	n := len(enzyme.RecognitionSite)
+	a, _ := strconv.Atoi(m[1])
+	b, _ := strconv.Atoi(m[2])
+	c, _ := strconv.Atoi(m[4])
+	d, _ := strconv.Atoi(m[5])
+	switch {
+	case m[1] != "":
+		enzyme.TopCut, enzyme.BottomCut = -a, -b
+		enzyme.NoPalinCleav = fmt.Sprintf("(%s/%s)(%s)", m[1], m[2], strings.Trim(m[4]+"/"+m[5], "/"))
+	default:
+		enzyme.TopCut, enzyme.BottomCut = n+c, n+d
+		enzyme.NoPalinCleav = fmt.Sprintf("()(%s/%s)", m[4], m[5])
+	}
+	enzyme.CutKnown = true
+	return enzyme, nil
+}
+
+// applyBairochSite parses the RS line of a bairoch record (e.g. "GAATTC, 1;" or "GGTCTC, 7; GAGACC, -5;")
+// into the recognition site and cut positions of `enzyme'
+func applyBairochSite(enzyme *RestrictEnzyme, rs string) error {
+	var entries []string
+	for _, e := range strings.Split(rs, ";") {
+		if e = strings.TrimSpace(e); e != "" {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	site, top, err := splitBairochEntry(entries[0])
+	if err != nil {
+		return err
+	}
+	enzyme.RecognitionSite = site
+	enzyme.NoPalinCleav = "no"
+	if top == nil {
+		return nil
+	}
+	n := len(site)
+	enzyme.TopCut, enzyme.BottomCut, enzyme.CutKnown = *top, n-*top, true
+	if len(entries) > 1 {
+		_, bottom, err := splitBairochEntry(entries[1])
+		if err != nil {
+			return err
+		}
+		if bottom != nil {
+			enzyme.BottomCut = n - *bottom
+		}
+	}
+	enzyme.NoPalinCleav = cleavageNotation(site, enzyme.TopCut, enzyme.BottomCut)
+	return nil
+}
+
+// splitBairochEntry splits a single "SITE, CUT" entry of a bairoch RS line; the returned cut is nil if it is "?"
+func splitBairochEntry(entry string) (string, *int, error) {
+	parts := strings.Split(entry, ",")
+	site := strings.ToUpper(strings.TrimSpace(parts[0]))
+	if (len(parts) < 2) || (strings.TrimSpace(parts[1]) == "?") {
+		return site, nil, nil
+	}
+	cut, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid cut position in %q: %v", entry, err)
+	}
+	return site, &cut, nil
+}
+
+// embossCut converts an EMBOSS cut position (which skips 0, i.e. -1 denotes a cut right before the first
+// nucleotide of the site) into the convention that is used by `RestrictEnzyme.TopCut'
+func embossCut(c int) int {
+	if c < 0 {
+		return c + 1
+	}
+	return c
+}
+
+// cleavageNotation returns the *.re `NoPalinCleav' notation for an enzyme with a single pair of cut positions:
+// "no" for palindromic sites with symmetric cuts, "()(x/y)" for cuts downstream and "(x/y)()" for cuts upstream of `site'
+func cleavageNotation(site string, top, bottom int) string {
+	n := len(site)
+	if (top >= 0) && (top <= n) && (top+bottom == n) && (reverseComplementIUPAC(site) == site) {
+		return "no"
+	}
+	if top > 0 {
+		return fmt.Sprintf("()(%d/%d)", top-n, bottom-n)
+	}
+	return fmt.Sprintf("(%d/%d)()", -top, -bottom)
+}
+
+// reverseComplementIUPAC returns the reverse complement of a recognition site that may contain IUPAC codes
+func reverseComplementIUPAC(site string) string {
+	pairs := map[byte]byte{
+		'A': 'T', 'T': 'A', 'C': 'G', 'G': 'C', 'R': 'Y', 'Y': 'R', 'K': 'M', 'M': 'K',
+		'S': 'S', 'W': 'W', 'B': 'V', 'V': 'B', 'D': 'H', 'H': 'D', 'N': 'N',
+	}
+	rc := make([]byte, len(site))
+	for i := 0; i < len(site); i++ {
+		c, ok := pairs[site[i]]
+		if !ok {
+			c = 'N'
+		}
+		rc[len(site)-1-i] = c
+	}
+	return string(rc)
+}
+
+// splitRebaseList splits a comma separated REBASE list (e.g. isoschizomers) and returns nil for empty input
+func splitRebaseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(strings.TrimSuffix(strings.TrimSpace(s), ";"), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitSupplierCodes splits a string of single letter supplier codes (e.g. "BFKNR" or "B, N.") into a slice
+func splitSupplierCodes(s string) []string {
+	var codes []string
+	for _, c := range s {
+		if (c >= 'A') && (c <= 'Z') {
+			codes = append(codes, string(c))
+		}
+	}
+	return codes
+}
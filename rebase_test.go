@@ -0,0 +1,167 @@
+package cloningprimer
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCaseRebase struct {
+	in   rebaseInput
+	want map[string]RestrictEnzyme
+	err  error
+}
+
+type rebaseInput struct {
+	file   string
+	format string
+}
+
+type testCaseSuppliers struct {
+	in   string
+	want map[string]string
+	err  error
+}
+
+func TestParseRebaseFromFile(t *testing.T) {
+	ecoRI := RestrictEnzyme{
+		Name:            "EcoRI",
+		RecognitionSite: "GAATTC",
+		NoPalinCleav:    "no",
+		Isoschizomeres:  []string{"AaaI", "AbfI"},
+		TopCut:          1,
+		BottomCut:       5,
+		CutKnown:        true,
+		Suppliers:       []string{"B", "N"},
+		MethylationSite: "3(6)",
+	}
+	bsaI := RestrictEnzyme{
+		Name:            "BsaI",
+		RecognitionSite: "GGTCTC",
+		NoPalinCleav:    "()(1/5)",
+		Isoschizomeres:  []string{"Eco31I"},
+		TopCut:          7,
+		BottomCut:       11,
+		CutKnown:        true,
+		Suppliers:       []string{"N"},
+	}
+	bthCI := RestrictEnzyme{
+		Name:            "BthCI",
+		RecognitionSite: "GCNGC",
+		NoPalinCleav:    "no",
+	}
+	cases := []testCaseRebase{
+		// test unknown format
+		{
+			in:   rebaseInput{"tests/withrefm.901", "gcg"},
+			want: nil,
+			err:  errors.New(`invalid input: unknown REBASE format "gcg" (must be one of withrefm, bairoch, emboss)`),
+		},
+		// test non-existing file
+		{
+			in:   rebaseInput{"tests/doesnotexist.901", RebaseWithrefm},
+			want: nil,
+			err:  errors.New("error opening file: open tests/doesnotexist.901: no such file or directory"),
+		},
+		// test withrefm format with a palindromic, a type IIS, a two-sided and an unknown cutter
+		// enzymes with unknown recognition sites (AbaUI) are skipped
+		{
+			in: rebaseInput{"tests/withrefm.901", RebaseWithrefm},
+			want: map[string]RestrictEnzyme{
+				"EcoRI": ecoRI,
+				"BsaI":  {Name: "BsaI", RecognitionSite: "GGTCTC", NoPalinCleav: "()(1/5)", Isoschizomeres: []string{"Eco31I"}, TopCut: 7, BottomCut: 11, CutKnown: true, Suppliers: []string{"N"}},
+				"BaeI":  {Name: "BaeI", RecognitionSite: "ACNNNNGTAYC", NoPalinCleav: "(10/15)(12/7)", TopCut: -10, BottomCut: -15, CutKnown: true, Suppliers: []string{"N"}},
+				"BthCI": bthCI,
+			},
+			err: nil,
+		},
+		// test bairoch format
+		{
+			in: rebaseInput{"tests/bairoch.901", RebaseBairoch},
+			want: map[string]RestrictEnzyme{
+				"EcoRI": ecoRI,
+				"BsaI":  bsaI,
+				"BthCI": bthCI,
+			},
+			err: nil,
+		},
+		// test emboss format, the emboss_r file in the same directory is picked up automatically
+		{
+			in: rebaseInput{"tests/emboss_e.901", RebaseEmboss},
+			want: map[string]RestrictEnzyme{
+				"EcoRI": ecoRI,
+				"BsaI":  bsaI,
+				"BaeI":  {Name: "BaeI", RecognitionSite: "ACNNNNGTAYC", NoPalinCleav: "(10/15)(12/7)", TopCut: -10, BottomCut: -15, CutKnown: true},
+				"EcoRV": {Name: "EcoRV", RecognitionSite: "GATATC", NoPalinCleav: "no", TopCut: 3, BottomCut: 3, CutKnown: true},
+			},
+			err: nil,
+		},
+		// test malformed emboss_e file
+		{
+			in:   rebaseInput{"tests/malformed_emboss_e.901", RebaseEmboss},
+			want: nil,
+			err:  errors.New("tests/malformed_emboss_e.901:1: expected 9 fields, found 6"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseRebaseFromFile(c.in.file, c.in.format)
+
+		// test similarity of expected and received value
+		if !isSimilarMap(got, c.want) {
+			t.Errorf("ParseRebaseFromFile(%v, %v) == %v, want %v\n", c.in.file, c.in.format, got, c.want)
+		}
+
+		// if no error is returned, test if none is expected
+		if err == nil && c.err != nil {
+			t.Errorf("ParseRebaseFromFile(%v, %v) == %v, want %v\n", c.in.file, c.in.format, err, c.err)
+		}
+
+		// if error is returned, test if an error is expected
+		if err != nil {
+			// if c.err is nil, print wanted and received errors
+			// else if an error is wanted and received but error messages are not the same
+			// print wanted and received error
+			if c.err == nil {
+				t.Errorf("ParseRebaseFromFile(%v, %v) == %v, want %v\n", c.in.file, c.in.format, err, c.err)
+			} else if err.Error() != c.err.Error() {
+				t.Errorf("ParseRebaseFromFile(%v, %v) == %v, want %v\n", c.in.file, c.in.format, err, c.err)
+			}
+		}
+	}
+}
+
+func TestParseRebaseSuppliersFromFile(t *testing.T) {
+	cases := []testCaseSuppliers{
+		{
+			in:   "tests/emboss_s.901",
+			want: map[string]string{"B": "Life Technologies (1/19)", "N": "New England Biolabs (1/19)"},
+			err:  nil,
+		},
+		{
+			in:   "tests/doesnotexist.901",
+			want: nil,
+			err:  errors.New("error opening file: open tests/doesnotexist.901: no such file or directory"),
+		},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		got, err := ParseRebaseSuppliersFromFile(c.in)
+
+		// test similarity of expected and received value
+		if len(got) != len(c.want) {
+			t.Errorf("ParseRebaseSuppliersFromFile(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+		for k, v := range c.want {
+			if got[k] != v {
+				t.Errorf("ParseRebaseSuppliersFromFile(%v) == %v, want %v\n", c.in, got, c.want)
+			}
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("ParseRebaseSuppliersFromFile(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
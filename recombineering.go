@@ -0,0 +1,130 @@
+package cloningprimer
+
+import "fmt"
+
+const (
+	// RecombineeringHomology gives the default length of the homology arms of primers for lambda Red recombineering
+	// and homologous recombination in yeast
+	RecombineeringHomology = 50
+
+	// RecombineeringMinHomology and RecombineeringMaxHomology give the recommended length range of the homology arms
+	RecombineeringMinHomology = 40
+	RecombineeringMaxHomology = 60
+)
+
+// RecombineeringOptions holds the parameters of `DesignRecombineering'
+type RecombineeringOptions struct {
+	InsertAt int     /* 1-based position of the locus behind which the cassette is placed */
+	Delete   int     /* number of locus nucleotides behind `InsertAt' that are replaced by the cassette */
+	Homology int     /* length of the homology arms (defaults to `RecombineeringHomology') */
+	AnnealTm float64 /* Tm of the annealing parts of the primers in °C (defaults to `AssemblyAnnealTm') */
+}
+
+// RecombineeringDesign holds the primers that amplify a selection cassette with homology arms for lambda Red
+// recombineering or homologous recombination in yeast and the locus after the recombination
+type RecombineeringDesign struct {
+	Locus       FastaRecord      /* the validated target locus */
+	Cassette    FastaRecord      /* the validated cassette template */
+	Primers     []AssemblyPrimer /* forward and reverse primer; the homology arms are their tails */
+	Product     string           /* sequence of the PCR product (cassette flanked by the homology arms) */
+	Recombinant string           /* sequence of the locus after the recombination */
+	Warnings    []string         /* homology arms and primers that do not meet all requirements */
+	InsertAt    int              /* 1-based position of the locus behind which the cassette is placed */
+	Homology    int              /* length of the homology arms */
+}
+
+// Record returns the recombinant locus as a linear GenBank record named `name' with features for the homology arms
+// and the cassette (a design without a recombinant locus has no features and one without homology arms no arms)
+func (d RecombineeringDesign) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("%s with %s", d.Locus.Name, d.Cassette.Name),
+		Molecule:   "DNA",
+		Topology:   "linear",
+		Division:   "SYN",
+		Sequence:   d.Recombinant,
+	}
+	if d.Recombinant == "" {
+		return r
+	}
+	start, end := d.InsertAt+1, d.InsertAt+len(d.Cassette.Sequence) /* position of the cassette */
+	arms := (d.Homology > 0) && (start-d.Homology >= 1) && (end+d.Homology <= len(d.Recombinant))
+	r.Features = append(r.Features, syntheticSource(len(d.Recombinant)))
+	if arms {
+		r.Features = append(r.Features, NewFeature("misc_feature", start-d.Homology, start-1, false, Qualifier{Key: "label", Value: "5' homology arm"}))
+	}
+	if (len(d.Cassette.Sequence) > 0) && (end <= len(d.Recombinant)) {
+		r.Features = append(r.Features, NewFeature("misc_feature", start, end, false, Qualifier{Key: "label", Value: d.Cassette.Name}))
+	}
+	if arms {
+		r.Features = append(r.Features, NewFeature("misc_feature", end+1, end+d.Homology, false, Qualifier{Key: "label", Value: "3' homology arm"}))
+	}
+	return r
+}
+
+// DesignRecombineering designs primers that amplify the `cassette' (e.g. an antibiotic resistance or auxotrophic
+// marker) with homology arms of `opts.Homology' nucleotides to the `locus', such that homologous recombination
+// replaces the `opts.Delete' locus nucleotides behind position `opts.InsertAt' with the cassette (an insertion if
+// nothing is deleted); the homology arms are added as tails to the annealing parts, which makes the primers longer
+// than `MaximumPrimerLength' (a warning is added if they exceed `MaximumLongPrimerLength')
+func DesignRecombineering(locus, cassette FastaRecord, opts RecombineeringOptions) (RecombineeringDesign, error) {
+	if opts.Homology == 0 {
+		opts.Homology = RecombineeringHomology
+	}
+	if opts.AnnealTm == 0 {
+		opts.AnnealTm = AssemblyAnnealTm
+	}
+	seq, err := ValidateSequence([]byte(locus.Sequence))
+	if err != nil {
+		return RecombineeringDesign{}, fmt.Errorf("invalid locus sequence: %v", err)
+	}
+	template, err := ValidateSequence([]byte(cassette.Sequence))
+	if err != nil {
+		return RecombineeringDesign{}, fmt.Errorf("invalid cassette sequence: %v", err)
+	}
+	switch {
+	case opts.Homology < MinimumPrimerLength:
+		return RecombineeringDesign{}, fmt.Errorf("invalid input: homology arms of %d nucleotides, expected at least %d", opts.Homology, MinimumPrimerLength)
+	case len(template) < MinimumPrimerLength:
+		return RecombineeringDesign{}, fmt.Errorf("invalid input: the cassette has %d nucleotides, expected at least %d", len(template), MinimumPrimerLength)
+	case (opts.InsertAt < opts.Homology) || (opts.Delete < 0) || (opts.InsertAt+opts.Delete+opts.Homology > len(seq)):
+		return RecombineeringDesign{}, fmt.Errorf("invalid input: insert position %d and deletion of %d nucleotides, expected homology arms of %d nucleotides on both sides within the locus (1-%d)", opts.InsertAt, opts.Delete, opts.Homology, len(seq))
+	}
+	if locus.Name == "" {
+		locus.Name = "locus"
+	}
+	if cassette.Name == "" {
+		cassette.Name = "cassette"
+	}
+	locus.Sequence, cassette.Sequence = seq, template
+	d := RecombineeringDesign{Locus: locus, Cassette: cassette, InsertAt: opts.InsertAt, Homology: opts.Homology}
+	if (opts.Homology < RecombineeringMinHomology) || (opts.Homology > RecombineeringMaxHomology) {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("homology arms of %d nucleotides are outside of the recommended range of %d-%d nucleotides", opts.Homology, RecombineeringMinHomology, RecombineeringMaxHomology))
+	}
+
+	// the homology arms flank the replaced part of the locus and must be unique in it
+	armUp := seq[opts.InsertAt-opts.Homology : opts.InsertAt]
+	armDown := seq[opts.InsertAt+opts.Delete : opts.InsertAt+opts.Delete+opts.Homology]
+	for _, arm := range []struct {
+		name string
+		seq  string
+	}{{"5'", armUp}, {"3'", armDown}} {
+		if n := len(FindSites(seq, arm.seq)); n != 1 {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("the %s homology arm occurs %d times in the locus", arm.name, n))
+		}
+	}
+	d.Product = armUp + template + armDown
+	d.Recombinant = seq[:opts.InsertAt] + template + seq[opts.InsertAt+opts.Delete:]
+
+	// the primers anneal to the ends of the cassette and carry the homology arms as tails
+	primers, warnings := tailedPrimers(opts.AnnealTm,
+		tailedPrimer{cassette.Name + "_" + locus.Name + "_F", template, armUp},
+		tailedPrimer{cassette.Name + "_" + locus.Name + "_R", reverseComplementIUPAC(template), reverseComplementIUPAC(armDown)})
+	d.Primers, d.Warnings = primers, append(d.Warnings, warnings...)
+	for _, p := range d.Primers {
+		if len(p.Sequence) > MaximumLongPrimerLength {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("primer %s has %d nucleotides, more than the %d nucleotides of standard oligo synthesis", p.Name, len(p.Sequence), MaximumLongPrimerLength))
+		}
+	}
+	return d, nil
+}
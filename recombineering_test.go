@@ -0,0 +1,87 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testKanR is the start of the kanamycin resistance gene (aph(3')-Ia) that is used as a selection cassette
+const testKanR = "ATGAGCCATATTCAACGGGAAACGTCTTGCTCGAGGCCGCGATTAAATTCCAACATGGATGCTGATTTATATGGGTATAAATGGGCTCGCGATAATGTCGGGCAATCAGGTGCGACAATC"
+
+func TestDesignRecombineering(t *testing.T) {
+	cases := []struct {
+		opts     RecombineeringOptions
+		primers  []string
+		warnings []string
+		err      error
+	}{
+		// the cassette replaces 30 nucleotides, the primers are longer than `MaximumPrimerLength'
+		{
+			opts:     RecombineeringOptions{InsertAt: 60, Delete: 30},
+			primers:  []string{"AGACCGTTCTGAAACGTATCGCTGAAGAACTGCGTGCTCTGGGCGTTAAAATGAGCCATATTCAACGGGAAACGT", "TTGCTCACCATTTAGCGTTTCACCAGACCTTCTTTACGGGACAGTTCATCGATTGTCGCACCTGATTGCCC"},
+			warnings: nil,
+			err:      nil,
+		},
+		// primers longer than `MaximumLongPrimerLength' are reported
+		{
+			opts: RecombineeringOptions{InsertAt: 100, Delete: 0, Homology: 100},
+			primers: []string{"ATGGCTAAAGAGACCGTTCTGAAACGTATCGCTGAAGAACTGCGTGCTCTGGGCGTTAAAGGTCTCTGCACCGAAGTTCTGCGTCAGCTGGATGAACTGTATGAGCCATATTCAACGGGAAACGT",
+				"TTTACGTCGCCGTCCAGCTCGACCAGGATGGGCACCACCCCGGTGAACAGCTCCTCGCCCTTGCTCACCATTTAGCGTTTCACCAGACCTTCTTTACGGGGATTGTCGCACCTGATTGCCC"},
+			warnings: []string{"homology arms of 100 nucleotides are outside of the recommended range of 40-60 nucleotides",
+				"primer kanR_locus_F has 125 nucleotides, more than the 120 nucleotides of standard oligo synthesis",
+				"primer kanR_locus_R has 121 nucleotides, more than the 120 nucleotides of standard oligo synthesis"},
+			err: nil,
+		},
+		{opts: RecombineeringOptions{InsertAt: 40, Delete: 30}, err: errors.New("invalid input: insert position 40 and deletion of 30 nucleotides, expected homology arms of 50 nucleotides on both sides within the locus (1-204)")},
+		{opts: RecombineeringOptions{InsertAt: 160, Delete: 0}, err: errors.New("invalid input: insert position 160 and deletion of 0 nucleotides, expected homology arms of 50 nucleotides on both sides within the locus (1-204)")},
+	}
+	for _, c := range cases {
+		d, err := DesignRecombineering(FastaRecord{Name: "locus", Sequence: testCDS + testGFP}, FastaRecord{Name: "kanR", Sequence: testKanR}, c.opts)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("DesignRecombineering(%+v) == %v, want %v\n", c.opts, err, c.err)
+		}
+		var primers []string
+		for _, p := range d.Primers {
+			primers = append(primers, p.Sequence)
+		}
+		if !reflect.DeepEqual(primers, c.primers) || !reflect.DeepEqual(d.Warnings, c.warnings) {
+			t.Errorf("DesignRecombineering(%+v) == %v, %q, want %v, %q\n", c.opts, primers, d.Warnings, c.primers, c.warnings)
+		}
+	}
+}
+
+func TestRecombineeringRecord(t *testing.T) {
+	locus := testCDS + testGFP
+	d, err := DesignRecombineering(FastaRecord{Name: "locus", Sequence: locus}, FastaRecord{Name: "kanR", Sequence: testKanR}, RecombineeringOptions{InsertAt: 60, Delete: 30})
+	if err != nil {
+		t.Fatalf("DesignRecombineering() == %v, want <nil>\n", err)
+	}
+	if want := locus[10:60] + testKanR + locus[90:140]; d.Product != want {
+		t.Errorf("DesignRecombineering() returned the product %v, want %v\n", d.Product, want)
+	}
+	r := d.Record("recombinant")
+	if want := locus[:60] + testKanR + locus[90:]; r.Sequence != want {
+		t.Errorf("Record() returned the sequence %v, want %v\n", r.Sequence, want)
+	}
+	var locations []string
+	for _, f := range r.Features {
+		locations = append(locations, f.Location)
+	}
+	if want := []string{"1..294", "11..60", "61..180", "181..230"}; !reflect.DeepEqual(locations, want) {
+		t.Errorf("Record() returned features at %v, want %v\n", locations, want)
+	}
+
+	// designs without a recombinant locus or homology arms do not get features outside of the sequence
+	if r := (RecombineeringDesign{}).Record("empty"); len(r.Features) != 0 {
+		t.Errorf("Record() of a zero design returned the features %v, want none\n", r.Features)
+	}
+	d.Homology = 0
+	locations = nil
+	for _, f := range d.Record("recombinant").Features {
+		locations = append(locations, f.Location)
+	}
+	if want := []string{"1..294", "61..180"}; !reflect.DeepEqual(locations, want) {
+		t.Errorf("Record() without homology arms returned features at %v, want %v\n", locations, want)
+	}
+}
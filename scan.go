@@ -0,0 +1,118 @@
+package cloningprimer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MutagenesisScan holds the mutagenesis designs of an alanine or saturation scan of a coding sequence
+type MutagenesisScan struct {
+	Name     string              /* name of the scanned template */
+	Designs  []MutagenesisDesign /* one design per mutated codon, in the order of the codons */
+	Warnings []string            /* codons that are skipped */
+}
+
+// AlanineScan designs mutagenesis primers (see `DesignMutagenesis') that replace every codon from `first' to `last'
+// of the circular plasmid `template' by an alanine codon; codons are counted from the 1-based position `frame',
+// alanine codons are replaced by glycine codons and stop codons are skipped
+func AlanineScan(template FastaRecord, frame, first, last int) (MutagenesisScan, error) {
+	if (first < 1) || (last < first) {
+		return MutagenesisScan{}, fmt.Errorf("invalid input: codon range %d-%d, expected 1 <= first <= last", first, last)
+	}
+	var codons []int
+	for c := first; c <= last; c++ {
+		codons = append(codons, c)
+	}
+	return scanCodons(template, frame, codons, func(aa byte) string {
+		if aa == 'A' {
+			return "G"
+		}
+		return "A"
+	})
+}
+
+// SaturationScan designs mutagenesis primers (see `DesignMutagenesis') that replace each of the `codons' of the
+// circular plasmid `template' by the `degenerate' codon (one of `SaturationCodons', e.g. NNK); codons are counted
+// from the 1-based position `frame' and stop codons are skipped
+func SaturationScan(template FastaRecord, frame int, codons []int, degenerate string) (MutagenesisScan, error) {
+	degenerate = strings.ToUpper(degenerate)
+	if !SaturationCodons[degenerate] {
+		return MutagenesisScan{}, fmt.Errorf("invalid input: degenerate codon %q, expected NNK, NNS or NNN", degenerate)
+	}
+	if len(codons) == 0 {
+		return MutagenesisScan{}, fmt.Errorf("invalid input: at least one codon is required")
+	}
+	seen := make(map[int]bool)
+	for _, c := range codons {
+		if seen[c] {
+			return MutagenesisScan{}, fmt.Errorf("invalid input: codon %d is listed more than once", c)
+		}
+		seen[c] = true
+	}
+	return scanCodons(template, frame, codons, func(byte) string { return degenerate })
+}
+
+// scanCodons designs mutagenesis primers for the `codons' of `template' that replace each codon by the amino acid or
+// degenerate codon returned by `target' for its amino acid; the primers are named after the template and the mutation
+func scanCodons(template FastaRecord, frame int, codons []int, target func(aa byte) string) (MutagenesisScan, error) {
+	seq, err := ValidateSequence([]byte(template.Sequence))
+	if err != nil {
+		return MutagenesisScan{}, fmt.Errorf("invalid sequence: %v", err)
+	}
+	if (frame < 1) || (frame > len(seq)) {
+		return MutagenesisScan{}, fmt.Errorf("invalid input: reading frame start %d, must be between 1 and %d", frame, len(seq))
+	}
+	protein, err := Translate(seq[frame-1:])
+	if err != nil {
+		return MutagenesisScan{}, err
+	}
+	if template.Name == "" {
+		template.Name = "template"
+	}
+	s := MutagenesisScan{Name: template.Name}
+	for _, c := range codons {
+		if (c < 1) || (c > len(protein)) {
+			return MutagenesisScan{}, fmt.Errorf("invalid input: codon %d is outside of the reading frame (1-%d)", c, len(protein))
+		}
+		aa := protein[c-1]
+		if aa == '*' {
+			s.Warnings = append(s.Warnings, fmt.Sprintf("codon %d is a stop codon and is skipped", c))
+			continue
+		}
+		spec := fmt.Sprintf("%c%d%s", aa, c, target(aa))
+		d, err := DesignMutagenesis(FastaRecord{Name: template.Name + "_" + spec, Sequence: seq}, spec, frame)
+		if err != nil {
+			return MutagenesisScan{}, err
+		}
+		s.Designs = append(s.Designs, d)
+	}
+	return s, nil
+}
+
+// WriteOligoTable writes the QuikChange (if `quikChange' is set) and Q5 primers (if `q5' is set) of `s' to `w' as a
+// CSV table for oligo orders with a header row; every row gives the primer name, its sequence (5' to 3', degenerate
+// nucleotides in IUPAC notation), length and Tm (of the complete QuikChange primer or the annealing part of a Q5
+// primer) and the mutation that it introduces
+func (s MutagenesisScan) WriteOligoTable(w io.Writer, quikChange, q5 bool) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"name", "sequence", "length", "tm", "mutation"}}
+	for _, d := range s.Designs {
+		var primers []AssemblyPrimer
+		if quikChange {
+			primers = append(primers, d.QuikChange...)
+		}
+		if q5 {
+			primers = append(primers, d.Q5...)
+		}
+		for _, p := range primers {
+			records = append(records, []string{p.Name, p.Sequence, strconv.Itoa(len(p.Sequence)), strconv.FormatFloat(p.Tm, 'f', 1, 64), d.Mutation.String()})
+		}
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("error writing CSV data: %v", err)
+	}
+	return nil
+}
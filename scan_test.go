@@ -0,0 +1,82 @@
+package cloningprimer
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAlanineScan(t *testing.T) {
+	cases := []struct {
+		first     int
+		last      int
+		mutations []string
+		err       error
+	}{
+		// alanine codons are replaced by glycine codons
+		{first: 2, last: 4, mutations: []string{"A2G (GCT>GGT)", "K3A (AAA>GCA)", "E4A (GAG>GCG)"}, err: nil},
+		{first: 4, last: 3, mutations: nil, err: errors.New("invalid input: codon range 4-3, expected 1 <= first <= last")},
+		{first: 42, last: 44, mutations: nil, err: errors.New("invalid input: codon 44 is outside of the reading frame (1-43)")},
+	}
+	for _, c := range cases {
+		s, err := AlanineScan(FastaRecord{Name: "gene", Sequence: testCDS}, 1, c.first, c.last)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("AlanineScan(%d, %d) == %v, want %v\n", c.first, c.last, err, c.err)
+		}
+		var mutations []string
+		for _, d := range s.Designs {
+			mutations = append(mutations, d.Mutation.String())
+		}
+		if !reflect.DeepEqual(mutations, c.mutations) {
+			t.Errorf("AlanineScan(%d, %d) == %v, want %v\n", c.first, c.last, mutations, c.mutations)
+		}
+	}
+}
+
+func TestSaturationScan(t *testing.T) {
+	cases := []struct {
+		codons     []int
+		degenerate string
+		mutations  []string
+		warnings   []string
+		err        error
+	}{
+		// stop codons are skipped
+		{codons: []int{5, 43}, degenerate: "nns", mutations: []string{"T5NNS (ACC>NNS)"}, warnings: []string{"codon 43 is a stop codon and is skipped"}, err: nil},
+		{codons: []int{3, 2}, degenerate: "NNK", mutations: []string{"K3NNK (AAA>NNK)", "A2NNK (GCT>NNK)"}, warnings: nil, err: nil},
+		{codons: []int{3, 3}, degenerate: "NNK", mutations: nil, warnings: nil, err: errors.New("invalid input: codon 3 is listed more than once")},
+		{codons: []int{3}, degenerate: "NNB", mutations: nil, warnings: nil, err: errors.New(`invalid input: degenerate codon "NNB", expected NNK, NNS or NNN`)},
+	}
+	for _, c := range cases {
+		s, err := SaturationScan(FastaRecord{Name: "gene", Sequence: testCDS}, 1, c.codons, c.degenerate)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("SaturationScan(%v, %v) == %v, want %v\n", c.codons, c.degenerate, err, c.err)
+		}
+		var mutations []string
+		for _, d := range s.Designs {
+			mutations = append(mutations, d.Mutation.String())
+		}
+		if !reflect.DeepEqual(mutations, c.mutations) || !reflect.DeepEqual(s.Warnings, c.warnings) {
+			t.Errorf("SaturationScan(%v, %v) == %v, %q, want %v, %q\n", c.codons, c.degenerate, mutations, s.Warnings, c.mutations, c.warnings)
+		}
+	}
+}
+
+func TestWriteOligoTable(t *testing.T) {
+	s, err := AlanineScan(FastaRecord{Name: "gene", Sequence: testCDS}, 1, 3, 3)
+	if err != nil {
+		t.Fatalf("AlanineScan(3, 3) == %v, want <nil>\n", err)
+	}
+	want := `name,sequence,length,tm,mutation
+gene_K3A_Q5_F,CGCTAAATGGCTGCAGAGACCGTTCTG,27,60.0,K3A (AAA>GCA)
+gene_K3A_Q5_R,TTTCACCAGACCTTCTTTACGGGACAG,27,60.2,K3A (AAA>GCA)
+`
+	var buf bytes.Buffer
+	if err := s.WriteOligoTable(&buf, false, true); err != nil {
+		t.Fatalf("WriteOligoTable() == %v, want <nil>\n", err)
+	}
+	if buf.String() != want {
+		t.Errorf("WriteOligoTable() == %q, want %q\n", buf.String(), want)
+	}
+}
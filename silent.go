@@ -0,0 +1,229 @@
+package cloningprimer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SilentMutation is a synonymous codon change that removes a restriction site from a coding sequence
+type SilentMutation struct {
+	Enzyme    string /* name of the enzyme whose site is removed */
+	Site      int    /* 1-based position of the removed site */
+	Codon     int    /* 1-based number of the changed codon (counted from the start of the reading frame) */
+	Position  int    /* 1-based position of the first nucleotide of the changed codon */
+	From      string /* original codon */
+	To        string /* synonymous codon that replaces `From' */
+	AminoAcid byte   /* one-letter code of the amino acid encoded by both codons */
+	Changes   int    /* number of changed nucleotides */
+}
+
+// String returns the mutation in the notation `<amino acid><codon number> <from>><to>', e.g. G8 GGT>GGC
+func (m SilentMutation) String() string {
+	return fmt.Sprintf("%c%d %s>%s", m.AminoAcid, m.Codon, m.From, m.To)
+}
+
+// SilentMutationPlan holds the silent mutations that remove restriction sites from a coding sequence and the primers
+// that introduce them by amplifying the sequence as overlapping fragments (e.g. for overlap extension PCR or an
+// assembly)
+type SilentMutationPlan struct {
+	Original  string           /* the validated input sequence */
+	Mutated   string           /* the sequence with all silent mutations */
+	Mutations []SilentMutation /* the mutations in the order of their position */
+	Fragments []FastaRecord    /* the overlapping fragments of `Mutated' that are amplified by the primers */
+	Primers   []AssemblyPrimer /* a forward and a reverse primer per fragment (none if there are no mutations) */
+	Warnings  []string         /* sites that cannot be removed and primers that do not meet all requirements */
+}
+
+// Record returns the mutated sequence as a linear GenBank record named `name' with a feature per silent mutation
+func (p SilentMutationPlan) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("%s with %d silent mutation(s)", name, len(p.Mutations)),
+		Molecule:   "DNA",
+		Topology:   "linear",
+		Division:   "SYN",
+		Sequence:   p.Mutated,
+	}
+	r.Features = append(r.Features, syntheticSource(len(p.Mutated)))
+	for _, m := range p.Mutations {
+		r.Features = append(r.Features, NewFeature("variation", m.Position, m.Position+Codon-1, false, Qualifier{Key: "label", Value: m.String()}, Qualifier{Key: "note", Value: fmt.Sprintf("silent mutation that removes the %s site at position %d", m.Enzyme, m.Site)}))
+	}
+	return r
+}
+
+// PlanSilentMutations removes all sites of `enzymes' from the coding sequence of `record' (whose reading frame starts
+// at the 1-based position `frame') by synonymous codon changes; every site is removed by the change with the fewest
+// changed nucleotides that does not create a new site of one of the `enzymes'; sites that cannot be removed (e.g.
+// because they lie outside of the reading frame) are reported as warnings; the mutations are introduced by primers
+// that split the sequence into overlapping fragments, with the mutations in the overlaps (mutations that are closer
+// than `MaximumPrimerLength' nucleotides share an overlap)
+func PlanSilentMutations(record FastaRecord, frame int, enzymes ...RestrictEnzyme) (SilentMutationPlan, error) {
+	if len(enzymes) == 0 {
+		return SilentMutationPlan{}, fmt.Errorf("invalid input: at least one enzyme is required")
+	}
+	seq, err := ValidateSequence([]byte(record.Sequence))
+	if err != nil {
+		return SilentMutationPlan{}, fmt.Errorf("invalid sequence: %v", err)
+	}
+	if (frame < 1) || (frame+Codon-1 > len(seq)) {
+		return SilentMutationPlan{}, fmt.Errorf("invalid input: reading frame start %d, must be between 1 and %d", frame, len(seq)-Codon+1)
+	}
+	if record.Name == "" {
+		record.Name = "cds"
+	}
+
+	// remove the sites one after another; every mutation reduces the number of sites, so this loop terminates
+	p := SilentMutationPlan{Original: seq, Mutated: seq}
+	skipped := make(map[string]bool) /* sites that cannot be removed */
+	for {
+		enzyme, site := firstSite(p.Mutated, enzymes, skipped)
+		if site == 0 {
+			break
+		}
+		m, err := silentMutation(p.Mutated, frame, enzyme, site, enzymes)
+		if err != nil {
+			p.Warnings = append(p.Warnings, err.Error())
+			skipped[fmt.Sprintf("%s:%d", enzyme.Name, site)] = true
+			continue
+		}
+		p.Mutated = p.Mutated[:m.Position-1] + m.To + p.Mutated[m.Position-1+Codon:]
+		p.Mutations = append(p.Mutations, m)
+	}
+	sort.Slice(p.Mutations, func(i, j int) bool { return p.Mutations[i].Position < p.Mutations[j].Position })
+	if len(p.Mutations) > 0 {
+		p.addSplitPrimers(record.Name)
+	}
+	return p, nil
+}
+
+// firstSite returns the enzyme and the 1-based position of the first site of `enzymes' in `seq' that is not in
+// `skipped' (position 0 if there is none)
+func firstSite(seq string, enzymes []RestrictEnzyme, skipped map[string]bool) (RestrictEnzyme, int) {
+	var first RestrictEnzyme
+	var position int
+	for _, e := range enzymes {
+		for _, s := range FindSites(seq, e.RecognitionSite) {
+			if !skipped[fmt.Sprintf("%s:%d", e.Name, s)] && ((position == 0) || (s < position)) {
+				first, position = e, s
+			}
+		}
+	}
+	return first, position
+}
+
+// countSites returns the total number of sites of `enzymes' in `seq'
+func countSites(seq string, enzymes []RestrictEnzyme) int {
+	var n int
+	for _, e := range enzymes {
+		n += len(FindSites(seq, e.RecognitionSite))
+	}
+	return n
+}
+
+// silentMutation returns the synonymous codon change with the fewest changed nucleotides that removes the site of
+// `enzyme' at the 1-based position `site' from `seq' without creating new sites of `enzymes'
+func silentMutation(seq string, frame int, enzyme RestrictEnzyme, site int, enzymes []RestrictEnzyme) (SilentMutation, error) {
+	var best SilentMutation
+	inFrame := false
+	before := countSites(seq, enzymes)
+	end := site - 1 + len(enzyme.RecognitionSite) /* 0-based end (exclusive) of the site */
+	for start := frame - 1; start < end; start += Codon {
+		if (start+Codon <= site-1) || (start+Codon > len(seq)) {
+			continue
+		}
+		inFrame = true
+		from := seq[start : start+Codon]
+		for _, to := range SynonymousCodons(from) {
+			mutated := seq[:start] + to + seq[start+Codon:]
+			if countSites(mutated, enzymes) >= before {
+				continue
+			}
+			removed := true
+			for _, s := range FindSites(mutated, enzyme.RecognitionSite) {
+				removed = removed && (s != site)
+			}
+			var changes int
+			for i := 0; i < Codon; i++ {
+				if from[i] != to[i] {
+					changes++
+				}
+			}
+			if removed && ((best.Changes == 0) || (changes < best.Changes)) {
+				best = SilentMutation{Enzyme: enzyme.Name, Site: site, Codon: (start-frame+1)/Codon + 1, Position: start + 1, From: from, To: to, AminoAcid: GeneticCode[from], Changes: changes}
+			}
+		}
+	}
+	switch {
+	case !inFrame:
+		return SilentMutation{}, fmt.Errorf("the %s site at position %d lies outside of the reading frame and cannot be removed by a silent mutation", enzyme.Name, site)
+	case best.Changes == 0:
+		return SilentMutation{}, fmt.Errorf("the %s site at position %d cannot be removed by a silent mutation", enzyme.Name, site)
+	}
+	return best, nil
+}
+
+// addSplitPrimers splits the mutated sequence of `p' into overlapping fragments with the mutations in the overlaps
+// and adds a forward and a reverse primer per fragment; the primers of an overlap start with the complete overlap
+// (up to and including the mutated codons, which are counted as tail) and anneal downstream of the mutations;
+// mutations that are closer than `MaximumPrimerLength' nucleotides to an end of the sequence are added to the tail of
+// the respective outer primer instead
+func (p *SilentMutationPlan) addSplitPrimers(name string) {
+	// group mutations that are too close to each other to be introduced by separate primers
+	var groups [][2]int /* 0-based start and end (exclusive) of the mutated region of every overlap */
+	for _, m := range p.Mutations {
+		if k := len(groups) - 1; (k >= 0) && (m.Position-1-groups[k][1] < MaximumPrimerLength) {
+			groups[k][1] = m.Position - 1 + Codon
+			continue
+		}
+		groups = append(groups, [2]int{m.Position - 1, m.Position - 1 + Codon})
+	}
+
+	// mutations close to the ends of the sequence are introduced by the outer primers
+	seq := p.Mutated
+	rc := reverseComplementIUPAC(seq)
+	outerF, outerR := "", ""
+	if groups[0][0] < MaximumPrimerLength {
+		outerF, groups = seq[:groups[0][1]], groups[1:]
+	}
+	if k := len(groups) - 1; (k >= 0) && (len(seq)-groups[k][1] < MaximumPrimerLength) {
+		outerR, groups = reverseComplementIUPAC(seq[groups[k][0]:]), groups[:k]
+	}
+
+	// every fragment starts at the overlap with the previous fragment and ends at the overlap with the next one
+	tailF, tailR := []string{outerF}, []string{}
+	starts, ends := []int{0}, []int{}
+	for _, g := range groups {
+		os, oe := mutationOverlap(seq, g[0], g[1])
+		tailR = append(tailR, reverseComplementIUPAC(seq[g[0]:oe]))
+		ends = append(ends, oe)
+		tailF = append(tailF, seq[os:g[1]])
+		starts = append(starts, os)
+	}
+	tailR = append(tailR, outerR)
+	ends = append(ends, len(seq))
+	for k := range starts {
+		f := FastaRecord{Name: fmt.Sprintf("%s_%d", name, k+1), Sequence: seq[starts[k]:ends[k]]}
+		p.Fragments = append(p.Fragments, f)
+		annealF, annealR := seq[starts[k]+len(tailF[k]):], rc[len(seq)-ends[k]+len(tailR[k]):]
+		primers, warnings := tailedPrimers(AssemblyAnnealTm, tailedPrimer{f.Name + "_F", annealF, tailF[k]}, tailedPrimer{f.Name + "_R", annealR, tailR[k]})
+		p.Primers = append(p.Primers, primers...)
+		p.Warnings = append(p.Warnings, warnings...)
+	}
+}
+
+// mutationOverlap returns the 0-based start and end (exclusive) of the overlap around the mutated region
+// `seq[start:end]'; the overlap is extended alternately to both sides until it is at least `GibsonMinOverlap'
+// nucleotides long and reaches a Tm of `GibsonOverlapTm' (or until it is `GibsonMaxOverlap' nucleotides long)
+func mutationOverlap(seq string, start, end int) (int, int) {
+	for left := true; (end-start < GibsonMaxOverlap) && ((start > 0) || (end < len(seq))); left = !left {
+		if tm, _ := CalculateTmNN(seq[start:end]); (end-start >= GibsonMinOverlap) && (tm >= GibsonOverlapTm) {
+			break
+		}
+		if (left && (start > 0)) || (end == len(seq)) {
+			start--
+		} else {
+			end++
+		}
+	}
+	return start, end
+}
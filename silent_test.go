@@ -0,0 +1,71 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testCDS has a BsaI site on the bottom strand (GAGACC at position 10) and one on the top strand (GGTCTC at 61)
+const testCDS = "ATGGCTAAAGAGACCGTTCTGAAACGTATCGCTGAAGAACTGCGTGCTCTGGGCGTTAAAGGTCTCTGCACCGAAGTTCTGCGTCAGCTGGATGAACTGTCCCGTAAAGAAGGTCTGGTGAAACGCTAA"
+
+func TestPlanSilentMutations(t *testing.T) {
+	cases := []struct {
+		frame     int
+		mutations []string
+		primers   []string
+		warnings  []string
+		err       error
+	}{
+		// the first mutation is introduced by the outer forward primer, the second one by the overlap of two fragments
+		{
+			frame:     1,
+			mutations: []string{"E4 GAG>GAA", "G21 GGT>GGA"},
+			primers:   []string{"ATGGCTAAAGAAACCGTTCTGAAACGTATCGCTGA", "GTGCAGAGTCCTTTAACGCCCAGAGCACGCA", "GGCGTTAAAGGACTCTGCACCGAAGTTCTGCG", "TTAGCGTTTCACCAGACCTTCTTTACG"},
+			warnings:  nil,
+			err:       nil,
+		},
+		// sites outside of the reading frame are kept
+		{
+			frame:     16,
+			mutations: []string{"G16 GGT>GGA"},
+			primers:   []string{"ATGGCTAAAGAGACCGTTCTGAAACG", "GTGCAGAGTCCTTTAACGCCCAGAGCACGCA", "GGCGTTAAAGGACTCTGCACCGAAGTTCTGCG", "TTAGCGTTTCACCAGACCTTCTTTACG"},
+			warnings:  []string{"the BsaI site at position 10 lies outside of the reading frame and cannot be removed by a silent mutation"},
+			err:       nil,
+		},
+		{frame: 0, err: errors.New("invalid input: reading frame start 0, must be between 1 and 127")},
+	}
+	for _, c := range cases {
+		p, err := PlanSilentMutations(FastaRecord{Name: "gene", Sequence: testCDS}, c.frame, testBsaI)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("PlanSilentMutations(%v) == %v, want %v\n", c.frame, err, c.err)
+		}
+		if err != nil {
+			continue
+		}
+		var mutations, primers []string
+		for _, m := range p.Mutations {
+			mutations = append(mutations, m.String())
+		}
+		for _, primer := range p.Primers {
+			primers = append(primers, primer.Sequence)
+		}
+		if !reflect.DeepEqual(mutations, c.mutations) || !reflect.DeepEqual(primers, c.primers) || !reflect.DeepEqual(p.Warnings, c.warnings) {
+			t.Errorf("PlanSilentMutations(%v) == %v, %v, %q, want %v, %v, %q\n", c.frame, mutations, primers, p.Warnings, c.mutations, c.primers, c.warnings)
+		}
+
+		// the protein is preserved and the fragments overlap such that they assemble into the mutated sequence
+		before, _ := Translate(p.Original[c.frame-1:])
+		after, _ := Translate(p.Mutated[c.frame-1:])
+		if before != after {
+			t.Errorf("PlanSilentMutations(%v) changed the protein %v to %v\n", c.frame, before, after)
+		}
+		if n := len(p.Fragments); (n != 2) || (p.Fragments[0].Sequence[:20] != p.Mutated[:20]) || (p.Fragments[n-1].Sequence[len(p.Fragments[n-1].Sequence)-20:] != p.Mutated[len(p.Mutated)-20:]) {
+			t.Errorf("PlanSilentMutations(%v) returned %d fragments that do not cover the mutated sequence\n", c.frame, n)
+		}
+	}
+
+	if _, err := PlanSilentMutations(FastaRecord{Sequence: testCDS}, 1); (err == nil) || (err.Error() != "invalid input: at least one enzyme is required") {
+		t.Errorf("PlanSilentMutations() == %v, want invalid input: at least one enzyme is required\n", err)
+	}
+}
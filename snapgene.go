@@ -0,0 +1,252 @@
+package cloningprimer
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FormatSnapGene identifies the binary SnapGene *.dna format
+const FormatSnapGene = "snapgene"
+
+// packet types of SnapGene *.dna files (every packet consists of a type byte, a 4-byte big-endian length and the data)
+const (
+	snapGeneSequencePacket = 0x00 /* flag byte (bit 0 is set for circular sequences) followed by the sequence */
+	snapGenePrimersPacket  = 0x05 /* XML description of the primers */
+	snapGeneNotesPacket    = 0x06 /* XML description of the file, e.g. its description and accession number */
+	snapGeneCookiePacket   = 0x09 /* "SnapGene" followed by version information, always the first packet */
+	snapGeneFeaturesPacket = 0x0a /* XML description of the features */
+)
+
+// htmlTagRegexp matches HTML tags in SnapGene descriptions and qualifier values (e.g. <b> or </html>)
+var htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// snapGeneFeatures, snapGenePrimers and snapGeneNotes mirror the XML data of the respective SnapGene packets
+type snapGeneFeatures struct {
+	Features []struct {
+		Name           string `xml:"name,attr"`
+		Type           string `xml:"type,attr"`
+		Directionality string `xml:"directionality,attr"` /* "1" for the top strand, "2" for the bottom strand */
+		Segments       []struct {
+			Range string `xml:"range,attr"` /* e.g. "11-40" */
+		} `xml:"Segment"`
+		Qualifiers []struct {
+			Name   string `xml:"name,attr"`
+			Values []struct {
+				Text   string `xml:"text,attr"`
+				Int    string `xml:"int,attr"`
+				Predef string `xml:"predef,attr"`
+			} `xml:"V"`
+		} `xml:"Q"`
+	} `xml:"Feature"`
+}
+
+type snapGenePrimers struct {
+	Primers []struct {
+		Name         string `xml:"name,attr"`
+		Sequence     string `xml:"sequence,attr"`
+		BindingSites []struct {
+			Location    string `xml:"location,attr"`    /* e.g. "11-28" */
+			BoundStrand string `xml:"boundStrand,attr"` /* "0" for the top strand, "1" for the bottom strand */
+		} `xml:"BindingSite"`
+	} `xml:"Primer"`
+}
+
+type snapGeneNotes struct {
+	Description     string `xml:"Description"`
+	AccessionNumber string `xml:"AccessionNumber"`
+}
+
+// ParseSnapGene parses a SnapGene *.dna file from `r' (gzip-compressed data is decompressed transparently); the
+// returned record holds the sequence, topology and features of the file and a primer_bind feature for every binding
+// site of its primers; the record has no name because SnapGene files are named after their file path
+func ParseSnapGene(r io.Reader) (GenBankRecord, error) {
+	b, err := readData(r)
+	if err != nil {
+		return GenBankRecord{}, err
+	}
+	return parseSnapGene(b, "input")
+}
+
+// ParseSnapGeneFromFile parses a (possibly gzip-compressed) SnapGene *.dna file (see `ParseSnapGene'); the returned
+// record is named after the file (e.g. "pUC19" for ./maps/pUC19.dna)
+func ParseSnapGeneFromFile(file string) (GenBankRecord, error) {
+	b, err := readFile(file)
+	if err != nil {
+		return GenBankRecord{}, err
+	}
+	r, err := parseSnapGene(b, file)
+	if err != nil {
+		return GenBankRecord{}, err
+	}
+	base := path.Base(strings.TrimSuffix(file, ".gz"))
+	r.Name = strings.TrimSuffix(base, path.Ext(base))
+	return r, nil
+}
+
+// isSnapGene returns true if `b' starts with the cookie packet of a SnapGene file
+func isSnapGene(b []byte) bool {
+	return (len(b) >= 13) && (b[0] == snapGeneCookiePacket) && (string(b[5:13]) == "SnapGene")
+}
+
+// parseSnapGene parses the contents `b' of a SnapGene *.dna file; `source' identifies the data in errors
+func parseSnapGene(b []byte, source string) (GenBankRecord, error) {
+	if !isSnapGene(b) {
+		return GenBankRecord{}, fmt.Errorf("invalid input: %s is not a SnapGene file", source)
+	}
+	record := GenBankRecord{Molecule: "DNA", Topology: "linear"}
+	var seqFound bool
+	var features, primers []Feature
+	for offset := 0; offset < len(b); {
+		if offset+5 > len(b) {
+			return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: truncated packet header at byte %d", source, offset)
+		}
+		kind, length := b[offset], int(binary.BigEndian.Uint32(b[offset+1:offset+5]))
+		if (length < 0) || (offset+5+length > len(b)) {
+			return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: packet at byte %d is truncated", source, offset)
+		}
+		data := b[offset+5 : offset+5+length]
+		offset += 5 + length
+
+		var err error
+		switch kind {
+		case snapGeneSequencePacket:
+			if len(data) == 0 {
+				return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: empty sequence packet", source)
+			}
+			if data[0]&0x01 != 0 {
+				record.Topology = "circular"
+			}
+			for i, l := range data[1:] {
+				if !IsIUPACNucleotide(l) {
+					return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: %v", source, &NucleotideError{Letter: rune(l), Position: i + 1, IUPAC: true})
+				}
+			}
+			record.Sequence, seqFound = string(data[1:]), true /* the case of the letters is preserved */
+		case snapGeneNotesPacket:
+			var notes snapGeneNotes
+			if err = xml.Unmarshal(data, &notes); err == nil {
+				record.Definition = strings.TrimSpace(htmlTagRegexp.ReplaceAllString(notes.Description, ""))
+				record.Accession = strings.TrimSpace(notes.AccessionNumber)
+			}
+		case snapGeneFeaturesPacket:
+			features, err = parseSnapGeneFeatures(data)
+		case snapGenePrimersPacket:
+			primers, err = parseSnapGenePrimers(data)
+		}
+		if err != nil {
+			return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: %v", source, err)
+		}
+	}
+	if !seqFound {
+		return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: no DNA sequence found", source)
+	}
+	record.Features = append(features, primers...)
+	for _, f := range record.Features {
+		if f.End > len(record.Sequence) {
+			return GenBankRecord{}, fmt.Errorf("invalid SnapGene data in %s: location %s of feature %s does not fit the sequence (%d nucleotides)", source, f.Location, f.Label(), len(record.Sequence))
+		}
+	}
+	logf("parsed SnapGene file '%s' (%d nucleotides, %d features)\n", source, len(record.Sequence), len(record.Features))
+	return record, nil
+}
+
+// parseSnapGeneFeatures converts the XML data of a SnapGene features packet into features; the name of a SnapGene
+// feature is stored as its label qualifier
+func parseSnapGeneFeatures(data []byte) ([]Feature, error) {
+	var x snapGeneFeatures
+	if err := xml.Unmarshal(data, &x); err != nil {
+		return nil, fmt.Errorf("cannot parse features: %v", err)
+	}
+	var features []Feature
+	for _, xf := range x.Features {
+		var ranges []string
+		for _, s := range xf.Segments {
+			ranges = append(ranges, s.Range)
+		}
+		f, err := snapGeneFeature(xf.Type, ranges, xf.Directionality == "2")
+		if err != nil {
+			return nil, fmt.Errorf("feature %s: %v", xf.Name, err)
+		}
+		if xf.Name != "" {
+			f.Qualifiers = append(f.Qualifiers, Qualifier{Key: "label", Value: xf.Name})
+		}
+		for _, q := range xf.Qualifiers {
+			for _, v := range q.Values {
+				value := v.Text
+				switch {
+				case v.Int != "":
+					value = v.Int
+				case v.Predef != "":
+					value = v.Predef
+				}
+				f.Qualifiers = append(f.Qualifiers, Qualifier{Key: q.Name, Value: strings.TrimSpace(htmlTagRegexp.ReplaceAllString(value, ""))})
+			}
+		}
+		features = append(features, f)
+	}
+	return features, nil
+}
+
+// parseSnapGenePrimers converts the XML data of a SnapGene primers packet into a primer_bind feature per binding site
+func parseSnapGenePrimers(data []byte) ([]Feature, error) {
+	var x snapGenePrimers
+	if err := xml.Unmarshal(data, &x); err != nil {
+		return nil, fmt.Errorf("cannot parse primers: %v", err)
+	}
+	var features []Feature
+	for _, p := range x.Primers {
+		for _, site := range p.BindingSites {
+			f, err := snapGeneFeature("primer_bind", []string{site.Location}, site.BoundStrand == "1")
+			if err != nil {
+				return nil, fmt.Errorf("primer %s: %v", p.Name, err)
+			}
+			f.Qualifiers = []Qualifier{{Key: "label", Value: p.Name}, {Key: "note", Value: "primer sequence " + strings.ToUpper(p.Sequence)}}
+			features = append(features, f)
+		}
+	}
+	return features, nil
+}
+
+// snapGeneFeature returns a feature with the key `key' that consists of the SnapGene `ranges' (e.g. "11-40")
+func snapGeneFeature(key string, ranges []string, complement bool) (Feature, error) {
+	var parts []string
+	for _, rng := range ranges {
+		bounds := strings.SplitN(rng, "-", 2)
+		if len(bounds) != 2 {
+			return Feature{}, fmt.Errorf("invalid range %q", rng)
+		}
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return Feature{}, fmt.Errorf("invalid range %q", rng)
+		}
+		end, err := strconv.Atoi(bounds[1])
+		if err != nil {
+			return Feature{}, fmt.Errorf("invalid range %q", rng)
+		}
+		if start > end {
+			return Feature{}, fmt.Errorf("range %q spans the origin of a circular sequence (not supported)", rng)
+		}
+		parts = append(parts, fmt.Sprintf("%d..%d", start, end))
+	}
+	if len(parts) == 0 {
+		return Feature{}, fmt.Errorf("no location")
+	}
+	loc := parts[0]
+	if len(parts) > 1 {
+		loc = "join(" + strings.Join(parts, ",") + ")"
+	}
+	if complement {
+		loc = "complement(" + loc + ")"
+	}
+	f := Feature{Key: key, Location: loc}
+	if err := parseLocation(&f); err != nil {
+		return Feature{}, err
+	}
+	return f, nil
+}
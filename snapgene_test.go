@@ -0,0 +1,128 @@
+package cloningprimer
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+type testCaseSnapGene struct {
+	in  string
+	err error
+}
+
+func TestParseSnapGeneFromFile(t *testing.T) {
+	r, err := ParseSnapGeneFromFile("tests/snapgene1.dna")
+	if err != nil {
+		t.Fatalf("ParseSnapGeneFromFile(tests/snapgene1.dna) == %v, want <nil>\n", err)
+	}
+
+	// test header data and sequence
+	got := []string{r.Name, r.Accession, r.Definition, r.Molecule, r.Topology}
+	want := []string{"snapgene1", "TEST0001", "Test plasmid with two coding sequences", "DNA", "circular"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSnapGeneFromFile(tests/snapgene1.dna) header == %q, want %q\n", got, want)
+	}
+	if (len(r.Sequence) != 124) || (r.Sequence[:10] != "ccggaattcc") {
+		t.Errorf("ParseSnapGeneFromFile(tests/snapgene1.dna) sequence == %s, want 124 lower case nucleotides\n", r.Sequence)
+	}
+
+	// test features and primers
+	wantFeatures := []Feature{
+		{Key: "CDS", Location: "11..40", Start: 11, End: 40, Qualifiers: []Qualifier{
+			{Key: "label", Value: "geneA"},
+			{Key: "codon_start", Value: "1"},
+			{Key: "gene", Value: "geneA"},
+			{Key: "locus_tag", Value: "TST_0001"},
+			{Key: "product", Value: "test protein A"},
+		}},
+		{Key: "CDS", Location: "complement(61..90)", Start: 61, End: 90, Complement: true, Qualifiers: []Qualifier{
+			{Key: "label", Value: "geneB"},
+			{Key: "locus_tag", Value: "TST_0002"},
+		}},
+		{Key: "misc_feature", Location: "join(1..5,100..110)", Start: 1, End: 110, Joined: true, Qualifiers: []Qualifier{
+			{Key: "label", Value: "split"},
+			{Key: "note", Value: "split feature"},
+		}},
+		{Key: "primer_bind", Location: "11..28", Start: 11, End: 28, Qualifiers: []Qualifier{
+			{Key: "label", Value: "P1"},
+			{Key: "note", Value: "primer sequence ATGAAACCCGGGTTTAAA"},
+		}},
+		{Key: "primer_bind", Location: "complement(64..79)", Start: 64, End: 79, Complement: true, Qualifiers: []Qualifier{
+			{Key: "label", Value: "P2"},
+			{Key: "note", Value: "primer sequence TTTCCCGGGAAATTTT"},
+		}},
+	}
+	if !reflect.DeepEqual(r.Features, wantFeatures) {
+		t.Errorf("ParseSnapGeneFromFile(tests/snapgene1.dna) features == %+v, want %+v\n", r.Features, wantFeatures)
+	}
+
+	// CDS features of SnapGene files can be selected like those of GenBank files
+	if f, err := r.FindCDS("TST_0002"); (err != nil) || (f.Qualifier("label") != "geneB") {
+		t.Errorf("FindCDS(TST_0002) == %+v, %v, want geneB, <nil>\n", f, err)
+	}
+}
+
+func TestParseSnapGeneErrors(t *testing.T) {
+	cases := []testCaseSnapGene{
+		{in: "tests/snapgene2.dna", err: errors.New("invalid SnapGene data in tests/snapgene2.dna: packet at byte 19 is truncated")},
+		{in: "tests/fasta1.fa", err: errors.New("invalid input: tests/fasta1.fa is not a SnapGene file")},
+		{in: "tests/nonexisting.dna", err: errors.New("error opening file: open tests/nonexisting.dna: no such file or directory")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		_, err := ParseSnapGeneFromFile(c.in)
+		if (err == nil) || (err.Error() != c.err.Error()) {
+			t.Errorf("ParseSnapGeneFromFile(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
+
+func TestParseSnapGeneSequence(t *testing.T) {
+	// IUPAC codes are accepted and the case of the letters is preserved
+	cookie := append([]byte{snapGeneCookiePacket, 0, 0, 0, 14}, "SnapGene\x00\x01\x00\x0f\x00\x13"...)
+	b := append(cookie, snapGeneSequencePacket, 0, 0, 0, 10, 0x00)
+	b = append(b, "ATGnnRTAA"...)
+	r, err := ParseSnapGene(bytes.NewReader(b))
+	if (r.Sequence != "ATGnnRTAA") || (r.Topology != "linear") || (err != nil) {
+		t.Errorf("ParseSnapGene(%q) == %v, %v, %v, want ATGnnRTAA, linear, <nil>\n", b, r.Sequence, r.Topology, err)
+	}
+
+	// other letters are rejected
+	b[len(b)-1] = 'X'
+	wantErr := "invalid SnapGene data in input: invalid input X at position 9, expected sequence of lower or upper case A,T,C,G or IUPAC codes"
+	if _, err := ParseSnapGene(bytes.NewReader(b)); (err == nil) || (err.Error() != wantErr) {
+		t.Errorf("ParseSnapGene(%q) == %v, want %v\n", b, err, wantErr)
+	}
+}
+
+func TestParseFeatureRecords(t *testing.T) {
+	// the format of annotated sequence data is detected from its contents
+	b, err := ioutil.ReadFile("tests/snapgene1.dna")
+	if err != nil {
+		t.Fatalf("error reading tests/snapgene1.dna: %v\n", err)
+	}
+	records, err := ParseFeatureRecords(bytes.NewReader(b))
+	if (err != nil) || (len(records) != 1) || (len(records[0].Features) != 5) || (records[0].Name != "") {
+		t.Fatalf("ParseFeatureRecords(tests/snapgene1.dna) == %+v, %v, want one record with 5 features, <nil>\n", records, err)
+	}
+	want := records[0].Sequence
+	records, err = ParseFeatureRecordsFromFile("tests/genbank1.gb")
+	if (err != nil) || (len(records) != 1) || (records[0].Name != "pTEST1") {
+		t.Errorf("ParseFeatureRecordsFromFile(tests/genbank1.gb) == %+v, %v, want record pTEST1, <nil>\n", records, err)
+	}
+	_, err = ParseFeatureRecordsFromFile("tests/fasta1.fa")
+	wantErr := "invalid input: expected GenBank, EMBL or SnapGene data in tests/fasta1.fa, found fasta data"
+	if (err == nil) || (err.Error() != wantErr) {
+		t.Errorf("ParseFeatureRecordsFromFile(tests/fasta1.fa) == %v, want %v\n", err, wantErr)
+	}
+
+	// SnapGene files are accepted wherever *.seq files are accepted
+	seq, err := ParseSequenceFromFile("tests/snapgene1.dna")
+	if (seq != want) || (err != nil) {
+		t.Errorf("ParseSequenceFromFile(tests/snapgene1.dna) == %v, %v, want %v, <nil>\n", seq, err, want)
+	}
+}
@@ -0,0 +1,195 @@
+package cloningprimer
+
+import "fmt"
+
+const (
+	// SOEMinOverlap gives the default minimum length of the overlaps of an overlap extension (SOE) PCR
+	SOEMinOverlap = 18
+
+	// SOEMaxOverlap gives the default maximum length of the overlaps of an overlap extension (SOE) PCR
+	SOEMaxOverlap = 30
+
+	// SOEOverlapTm gives the default minimum Tm (in °C, see `CalculateTmNN') of the overlaps of an overlap extension
+	// (SOE) PCR, which must anneal at the annealing temperature of the fusion PCR
+	SOEOverlapTm = 55.0
+)
+
+// SOEOptions holds the parameters of `DesignSOE'; zero values select the respective defaults
+type SOEOptions struct {
+	Linkers    []string /* sequences that are inserted at the junctions (one per junction, empty for none) */
+	MinOverlap int      /* minimum length of the overlaps (defaults to `SOEMinOverlap') */
+	MaxOverlap int      /* maximum length of the overlaps (defaults to `SOEMaxOverlap') */
+	OverlapTm  float64  /* minimum Tm of the overlaps in °C (defaults to `SOEOverlapTm') */
+	AnnealTm   float64  /* Tm of the annealing parts of the primers in °C (defaults to `AssemblyAnnealTm') */
+}
+
+// SOEDesign holds the primers of an overlap extension (SOE) PCR that fuses two or more fragments and the fused product
+type SOEDesign struct {
+	Fragments []FastaRecord      /* the fragments in the order of the product */
+	Linkers   []string           /* the linkers between the fragments */
+	Primers   []AssemblyPrimer   /* a forward and a reverse primer per fragment; the outer primers have no tails */
+	Junctions []AssemblyJunction /* the overlaps of the junctions between adjacent fragments (including the linkers) */
+	Amplicons []FastaRecord      /* the products of the first PCRs that are fused by the overlaps */
+	Product   string             /* sequence of the fused (linear) product */
+	Warnings  []string           /* overlaps or primers that do not meet all requirements */
+}
+
+// Record returns the fused product as a linear GenBank record named `name' with a feature per fragment, linker and
+// overlap
+func (d SOEDesign) Record(name string) GenBankRecord {
+	r := GenBankRecord{
+		Name:       name,
+		Definition: fmt.Sprintf("overlap extension PCR fusion of %d fragments", len(d.Fragments)),
+		Molecule:   "DNA",
+		Topology:   "linear",
+		Division:   "SYN",
+		Sequence:   d.Product,
+	}
+	r.Features = append(r.Features, syntheticSource(len(d.Product)))
+	start := 1
+	var overlaps []Feature /* the overlap of junction k starts in fragment k and spans its linker */
+	for k, f := range d.Fragments {
+		r.Features = append(r.Features, NewFeature("misc_feature", start, start+len(f.Sequence)-1, false, Qualifier{Key: "label", Value: f.Name}))
+		start += len(f.Sequence)
+		if k >= len(d.Linkers) {
+			continue
+		}
+		if k < len(d.Junctions) {
+			j := d.Junctions[k]
+			fromLeft, _ := soeOverlapSplit(len(j.Overlap), len(d.Linkers[k]))
+			overlaps = append(overlaps, NewFeature("misc_feature", start-fromLeft, start-fromLeft+len(j.Overlap)-1, false,
+				Qualifier{Key: "label", Value: fmt.Sprintf("overlap %s/%s", j.Left, j.Right)}, Qualifier{Key: "note", Value: fmt.Sprintf("Tm %.1f C", j.Tm)}))
+		}
+		if d.Linkers[k] != "" {
+			r.Features = append(r.Features, NewFeature("misc_feature", start, start+len(d.Linkers[k])-1, false, Qualifier{Key: "label", Value: fmt.Sprintf("linker %s/%s", f.Name, d.Fragments[k+1].Name)}))
+			start += len(d.Linkers[k])
+		}
+	}
+	r.Features = append(r.Features, overlaps...)
+	return r
+}
+
+// DesignSOE designs primers for an overlap extension (SOE) PCR that fuses the `fragments' in the given order, with
+// the optional `opts.Linkers' between them; the outer primers (forward primer of the first and reverse primer of the
+// last fragment) only anneal to their fragment, the inner primers are chimeric: their tails add the linker and the
+// part of the overlap that belongs to the neighboring fragment; for every junction, the shortest overlap (including
+// the linker) between `opts.MinOverlap' and `opts.MaxOverlap' is chosen whose Tm reaches `opts.OverlapTm' and that is
+// unique in the product and does not form a hairpin stem of more than `MaxHairpinStem' nucleotides; if no overlap
+// meets all requirements, the shortest overlap that reaches the Tm is used and a warning is added to the result
+func DesignSOE(fragments []FastaRecord, opts SOEOptions) (SOEDesign, error) {
+	if opts.MinOverlap == 0 {
+		opts.MinOverlap = SOEMinOverlap
+	}
+	if opts.MaxOverlap == 0 {
+		opts.MaxOverlap = SOEMaxOverlap
+	}
+	if opts.OverlapTm == 0 {
+		opts.OverlapTm = SOEOverlapTm
+	}
+	if opts.AnnealTm == 0 {
+		opts.AnnealTm = AssemblyAnnealTm
+	}
+	switch {
+	case len(fragments) < 2:
+		return SOEDesign{}, fmt.Errorf("invalid input: an overlap extension PCR fuses at least 2 fragments, not %d", len(fragments))
+	case (len(opts.Linkers) > 0) && (len(opts.Linkers) != len(fragments)-1):
+		return SOEDesign{}, fmt.Errorf("invalid input: %d linker(s) for %d junction(s)", len(opts.Linkers), len(fragments)-1)
+	case (opts.MinOverlap < MinimumPrimerLength) || (opts.MaxOverlap < opts.MinOverlap):
+		return SOEDesign{}, fmt.Errorf("invalid input: overlaps of %d-%d nucleotides, expected a minimum >= %d and a maximum >= the minimum", opts.MinOverlap, opts.MaxOverlap, MinimumPrimerLength)
+	}
+
+	// validate the fragments and linkers and fuse them
+	var d SOEDesign
+	for i, f := range fragments {
+		seq, err := ValidateSequence([]byte(f.Sequence))
+		if err != nil {
+			return SOEDesign{}, fmt.Errorf("invalid sequence of fragment %d: %v", i+1, err)
+		}
+		if len(seq) < MinimumPrimerLength {
+			return SOEDesign{}, fmt.Errorf("invalid input: fragment %d has %d nucleotides, expected at least %d", i+1, len(seq), MinimumPrimerLength)
+		}
+		if f.Name == "" {
+			f.Name = fmt.Sprintf("fragment%d", i+1)
+		}
+		f.Sequence = seq
+		d.Fragments = append(d.Fragments, f)
+		d.Product += seq
+		if i == len(fragments)-1 {
+			break
+		}
+		var linker string
+		if len(opts.Linkers) > 0 {
+			if linker, err = ValidateSequence([]byte(opts.Linkers[i])); err != nil {
+				return SOEDesign{}, fmt.Errorf("invalid sequence of linker %d: %v", i+1, err)
+			}
+		}
+		if len(linker) > opts.MaxOverlap {
+			return SOEDesign{}, fmt.Errorf("invalid input: linker %d has %d nucleotides, expected at most %d (the maximum overlap)", i+1, len(linker), opts.MaxOverlap)
+		}
+		d.Linkers = append(d.Linkers, linker)
+		d.Product += linker
+	}
+
+	// choose the overlap of every junction (fragment k and its successor) around the linker; the reverse primer of
+	// fragment k adds the linker and the start of fragment k+1, the forward primer of fragment k+1 adds the end of
+	// fragment k and the linker
+	n := len(d.Fragments)
+	tailF, tailR := make([]string, n), make([]string, n) /* tails of the forward and reverse primer of every fragment */
+	for k := 0; k < n-1; k++ {
+		left, right, linker := d.Fragments[k], d.Fragments[k+1], d.Linkers[k]
+		var best *AssemblyJunction
+		var bestLeft, bestRight int
+		for l := opts.MinOverlap; l <= opts.MaxOverlap; l++ {
+			fromLeft, fromRight := soeOverlapSplit(l, len(linker))
+			if (l < len(linker)) || (fromLeft > len(left.Sequence)) || (fromRight > len(right.Sequence)) {
+				continue
+			}
+			s := left.Sequence[len(left.Sequence)-fromLeft:] + linker + right.Sequence[:fromRight]
+			tm, _ := CalculateTmNN(s)
+			occurrences := len(FindSites(d.Product, s))
+			if reverseComplementIUPAC(s) == s {
+				occurrences *= 2
+			}
+			j := AssemblyJunction{Left: left.Name, Right: right.Name, Overlap: s, Tm: tm, Unique: occurrences == 1, HairpinStem: hairpinStem(s)}
+			if j.Tm < opts.OverlapTm {
+				continue
+			}
+			if best == nil {
+				best, bestLeft, bestRight = &j, fromLeft, fromRight
+			}
+			if j.Unique && (j.HairpinStem <= MaxHairpinStem) {
+				best, bestLeft, bestRight = &j, fromLeft, fromRight
+				break
+			}
+		}
+		if best == nil {
+			return SOEDesign{}, fmt.Errorf("invalid input: no overlap of %d-%d nucleotides between %s and %s reaches a Tm of %.1f°C", opts.MinOverlap, opts.MaxOverlap, left.Name, right.Name, opts.OverlapTm)
+		}
+		d.Junctions = append(d.Junctions, *best)
+		if !best.Unique {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("the overlap %s between %s and %s is not unique in the product", best.Overlap, best.Left, best.Right))
+		}
+		if best.HairpinStem > MaxHairpinStem {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("the overlap %s between %s and %s can form a hairpin with a stem of %d nucleotides", best.Overlap, best.Left, best.Right, best.HairpinStem))
+		}
+		tailR[k] = reverseComplementIUPAC(linker + right.Sequence[:bestRight])
+		tailF[k+1] = left.Sequence[len(left.Sequence)-bestLeft:] + linker
+	}
+
+	// every fragment is amplified with the tails of its junctions
+	for k, f := range d.Fragments {
+		primers, warnings := tailedPrimers(opts.AnnealTm, tailedPrimer{f.Name + "_F", f.Sequence, tailF[k]}, tailedPrimer{f.Name + "_R", reverseComplementIUPAC(f.Sequence), tailR[k]})
+		d.Primers = append(d.Primers, primers...)
+		d.Warnings = append(d.Warnings, warnings...)
+		d.Amplicons = append(d.Amplicons, FastaRecord{Name: f.Name + "_PCR", Sequence: tailF[k] + f.Sequence + reverseComplementIUPAC(tailR[k])})
+	}
+	return d, nil
+}
+
+// soeOverlapSplit returns the number of nucleotides of an overlap of length `l' that come from the fragments on the
+// left and on the right of a linker of length `linker' (the overlap is centred on the linker and `l' must be at least
+// `linker')
+func soeOverlapSplit(l, linker int) (int, int) {
+	fromLeft := (l - linker) / 2
+	return fromLeft, l - linker - fromLeft
+}
@@ -0,0 +1,104 @@
+package cloningprimer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testGFP is the start of the EGFP coding sequence
+const testGFP = "ATGGTGAGCAAGGGCGAGGAGCTGTTCACCGGGGTGGTGCCCATCCTGGTCGAGCTGGACGGCGACGTAAACGGC"
+
+func TestDesignSOE(t *testing.T) {
+	gene, gfp := FastaRecord{Name: "gene", Sequence: testCDS[:60]}, FastaRecord{Name: "gfp", Sequence: testGFP}
+	cases := []struct {
+		fragments []FastaRecord
+		linkers   []string
+		primers   []string
+		overlaps  []string
+		err       error
+	}{
+		// the outer primers have no tails, the overlap is split between the inner primers
+		{
+			fragments: []FastaRecord{gene, gfp},
+			linkers:   nil,
+			primers:   []string{"ATGGCTAAAGAGACCGTTCTGAAACG", "TGCTCACCATTTTAACGCCCAGAGCACGCA", "GGGCGTTAAAATGGTGAGCAAGGGCGAGG", "GCCGTTTACGTCGCCGTC"},
+			overlaps:  []string{"GGGCGTTAAAATGGTGAGCA"},
+			err:       nil,
+		},
+		// both inner primers add the linker
+		{
+			fragments: []FastaRecord{gene, gfp},
+			linkers:   []string{"ggtggcggtggctct"},
+			primers:   []string{"ATGGCTAAAGAGACCGTTCTGAAACG", "ATAGAGCCACCGCCACCTTTAACGCCCAGAGCACGCA", "AGGTGGCGGTGGCTCTATGGTGAGCAAGGGCGAGG", "GCCGTTTACGTCGCCGTC"},
+			overlaps:  []string{"AGGTGGCGGTGGCTCTAT"},
+			err:       nil,
+		},
+		{fragments: []FastaRecord{gene}, err: errors.New("invalid input: an overlap extension PCR fuses at least 2 fragments, not 1")},
+		{fragments: []FastaRecord{gene, gfp}, linkers: []string{"GGT", "GGT"}, err: errors.New("invalid input: 2 linker(s) for 1 junction(s)")},
+		{fragments: []FastaRecord{gene, gfp}, linkers: []string{strings.Repeat("GGTGGCTCT", 4)}, err: errors.New("invalid input: linker 1 has 36 nucleotides, expected at most 30 (the maximum overlap)")},
+	}
+	for _, c := range cases {
+		d, err := DesignSOE(c.fragments, SOEOptions{Linkers: c.linkers})
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("DesignSOE(%v) == %v, want %v\n", c.linkers, err, c.err)
+		}
+		var primers, overlaps []string
+		for _, p := range d.Primers {
+			primers = append(primers, p.Sequence)
+		}
+		for _, j := range d.Junctions {
+			overlaps = append(overlaps, j.Overlap)
+		}
+		if !reflect.DeepEqual(primers, c.primers) || !reflect.DeepEqual(overlaps, c.overlaps) {
+			t.Errorf("DesignSOE(%v) == %v, %v, want %v, %v\n", c.linkers, primers, overlaps, c.primers, c.overlaps)
+		}
+		if (err == nil) && (d.Product != gene.Sequence+strings.ToUpper(strings.Join(c.linkers, ""))+gfp.Sequence) {
+			t.Errorf("DesignSOE(%v) returned the product %v\n", c.linkers, d.Product)
+		}
+	}
+}
+
+func TestSOEAmplicons(t *testing.T) {
+	d, err := DesignSOE([]FastaRecord{{Name: "gene", Sequence: testCDS[:60]}, {Name: "gfp", Sequence: testGFP}}, SOEOptions{})
+	if err != nil {
+		t.Fatalf("DesignSOE() == %v, want <nil>\n", err)
+	}
+
+	// the first PCR products overlap by exactly the junction overlap and are fused into the product
+	left, right, overlap := d.Amplicons[0].Sequence, d.Amplicons[1].Sequence, d.Junctions[0].Overlap
+	if !strings.HasSuffix(left, overlap) || !strings.HasPrefix(right, overlap) || (left+right[len(overlap):] != d.Product) {
+		t.Errorf("DesignSOE() returned the amplicons %v and %v, which do not fuse into %v\n", left, right, d.Product)
+	}
+}
+
+func TestSOELongLinker(t *testing.T) {
+	// a linker that is longer than the minimum overlap is part of every overlap (shorter overlaps are skipped)
+	linker := "GGTGGCGGTGGCTCTGGCG"
+	d, err := DesignSOE([]FastaRecord{{Name: "gene", Sequence: testCDS[:60]}, {Name: "gfp", Sequence: testGFP}}, SOEOptions{Linkers: []string{linker}})
+	if err != nil {
+		t.Fatalf("DesignSOE() == %v, want <nil>\n", err)
+	}
+	left, right, overlap := d.Amplicons[0].Sequence, d.Amplicons[1].Sequence, d.Junctions[0].Overlap
+	if !strings.Contains(overlap, linker) || !strings.HasSuffix(left, overlap) || !strings.HasPrefix(right, overlap) || (left+right[len(overlap):] != d.Product) {
+		t.Errorf("DesignSOE() returned the overlap %v and the amplicons %v and %v, want an overlap with the linker %v\n", overlap, left, right, linker)
+	}
+}
+
+func TestSOERecord(t *testing.T) {
+	// the overlap feature marks the junction even if the overlap also occurs elsewhere in the product
+	gene := FastaRecord{Name: "gene", Sequence: "GGGCGTTAAAATGGTGAGCA" + testCDS[:60]}
+	d, err := DesignSOE([]FastaRecord{gene, {Name: "gfp", Sequence: testGFP}}, SOEOptions{MinOverlap: 20, MaxOverlap: 20})
+	if err != nil {
+		t.Fatalf("DesignSOE() == %v, want <nil>\n", err)
+	}
+	var locations []string
+	for _, f := range d.Record("fusion").Features {
+		locations = append(locations, f.Location)
+	}
+	want := []string{"1..155", "1..80", "81..155", "71..90"}
+	if !reflect.DeepEqual(locations, want) {
+		t.Errorf("Record() has the feature locations %v, want %v\n", locations, want)
+	}
+}
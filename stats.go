@@ -3,6 +3,7 @@ package cloningprimer
 import (
 	"errors"
 	"fmt"
+	"math"
 )
 
 // CalculateGC takes a `primer' as an input and returns the GC nucleotide content as a floating point number between 0.0 and 1.0
@@ -71,3 +72,60 @@ func CalculateTm(primer string, complementary int) (float64, error) {
 	}
 	return (2 * atSum) + (4 * gcSum), nil
 }
+
+const (
+	// SaltConcentration gives the monovalent cation (Na+) concentration in mol/l that `CalculateTmNN' assumes
+	SaltConcentration = 0.05
+
+	// PrimerConcentration gives the primer concentration in mol/l that `CalculateTmNN' assumes
+	PrimerConcentration = 250e-9
+)
+
+// nearestNeighbors holds the enthalpy (kcal/mol) and entropy (cal/(K*mol)) of every nearest neighbor pair (5' -> 3'
+// on the top strand) of the unified parameters of SantaLucia (1998); a pair and its reverse complement (e.g. AA and TT)
+// share the same values
+var nearestNeighbors = map[string][2]float64{
+	"AA": {-7.9, -22.2}, "TT": {-7.9, -22.2}, "AT": {-7.2, -20.4}, "TA": {-7.2, -21.3},
+	"CA": {-8.5, -22.7}, "TG": {-8.5, -22.7}, "GT": {-8.4, -22.4}, "AC": {-8.4, -22.4},
+	"CT": {-7.8, -21.0}, "AG": {-7.8, -21.0}, "GA": {-8.2, -22.2}, "TC": {-8.2, -22.2},
+	"CG": {-10.6, -27.2}, "GC": {-9.8, -24.4}, "GG": {-8.0, -19.9}, "CC": {-8.0, -19.9},
+}
+
+// CalculateTmNN takes a sequence `seq' (5' -> 3') as an input and returns its melting temperature (or Tm) in °C as a
+// floating point number; in contrast to `CalculateTm', it uses the nearest neighbor method with the unified parameters
+// of SantaLucia (1998), a salt correction for `SaltConcentration' and the `PrimerConcentration', and is suitable for
+// longer sequences like the overlaps of assembly primers
+func CalculateTmNN(seq string) (float64, error) {
+	// check validity of input
+	if len(seq) < 2 {
+		return 0.0, fmt.Errorf("invalid input: the nearest neighbor method needs at least 2 nucleotides, not %d", len(seq))
+	}
+	s, err := ValidateSequence([]byte(seq))
+	if err != nil {
+		return 0.0, fmt.Errorf("error while calculating Tm: %v", err)
+	}
+
+	// sum up the initiation parameters of the terminal base pairs and the parameters of all nearest neighbor pairs
+	var dh, ds float64
+	for _, terminal := range []byte{s[0], s[len(s)-1]} {
+		switch terminal {
+		case 'G', 'C':
+			dh, ds = dh+0.1, ds-2.8
+		case 'A', 'T':
+			dh, ds = dh+2.3, ds+4.1
+		}
+	}
+	for i := 0; i < len(s)-1; i++ {
+		p := nearestNeighbors[s[i:i+2]]
+		dh, ds = dh+p[0], ds+p[1]
+	}
+
+	// self-complementary sequences bind to themselves, other primers to their (excess) template
+	conc := PrimerConcentration / 4
+	if reverseComplementIUPAC(s) == s {
+		ds -= 1.4
+		conc = PrimerConcentration
+	}
+	ds += 0.368 * float64(len(s)-1) * math.Log(SaltConcentration) /* salt correction of the entropy */
+	return dh*1000/(ds+1.987*math.Log(conc)) - 273.15, nil
+}
@@ -2,6 +2,7 @@ package cloningprimer
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -145,3 +146,38 @@ func TestCalculateTm(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculateTmNN(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string /* Tm rounded to two decimal places */
+		err  error
+	}{
+		{in: "AGCGGATAACAATTTCACACAGGA", want: "56.72", err: nil},
+		{in: "gtaaaacgacggccagt", want: "51.67", err: nil},
+		{in: "AAAAAAAAAAAAAAAAAAAA", want: "38.08", err: nil},
+		{in: "GCGGCCGCGGCCGCGG", want: "70.53", err: nil},
+		{in: "ATGCGTACGTTAGCCATGCAGGGCCCGCGATTTACG", want: "71.62", err: nil},
+		// self-complementary sequences bind to themselves (symmetry correction, full primer concentration)
+		{in: "GAATTCGAATTC", want: "29.29", err: nil},
+		{in: "gaattcGAATTC", want: "29.29", err: nil},
+		{in: "CTGCAGCTGCAGCTGCAG", want: "59.92", err: nil},
+		{in: "A", want: "0.00", err: errors.New("invalid input: the nearest neighbor method needs at least 2 nucleotides, not 1")},
+		{in: "AGAGACGCGAQ", want: "0.00", err: errors.New("error while calculating Tm: invalid char in nucleotide sequence: Q")},
+	}
+
+	// loop over test cases
+	for _, c := range cases {
+		tm, err := CalculateTmNN(c.in)
+
+		// test similarity of expected and received value
+		if got := fmt.Sprintf("%.2f", tm); got != c.want {
+			t.Errorf("CalculateTmNN(%v) == %v, want %v\n", c.in, got, c.want)
+		}
+
+		// test if the received error matches the expected error
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("CalculateTmNN(%v) == %v, want %v\n", c.in, err, c.err)
+		}
+	}
+}
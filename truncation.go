@@ -0,0 +1,186 @@
+package cloningprimer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// TruncationOptions holds the parameters of `DesignTruncations'; the primers are designed like those of `FindForward'
+// and `FindReverse'
+type TruncationOptions struct {
+	Starts    []int  /* first codons of the constructs (1 keeps the N-terminus) */
+	Ends      []int  /* last codons of the constructs (the last codon of the reading frame keeps the C-terminus) */
+	Frame     int    /* 1-based position of the first nucleotide of the reading frame (defaults to 1) */
+	RestrictF string /* recognition site of the restriction enzyme that is added to the forward primers */
+	RestrictR string /* recognition site of the restriction enzyme that is added to the reverse primers */
+	LengthF   int    /* number of complementary nucleotides of the forward primers */
+	LengthR   int    /* number of complementary nucleotides of the reverse primers */
+	RandomF   int    /* number of random nucleotides in front of the restriction site of the forward primers */
+	RandomR   int    /* number of random nucleotides in front of the restriction site of the reverse primers */
+}
+
+// TruncationPrimer is a forward or reverse primer of a truncation series
+type TruncationPrimer struct {
+	Name     string /* e.g. gene_F12 for a forward primer whose construct starts with codon 12 */
+	Sequence string /* 5' to 3' */
+	Codons   []int  /* first (forward primer) or last (reverse primer) codons of the constructs that use the primer */
+}
+
+// TruncationConstruct is an N- and/or C-terminally truncated construct of a truncation series
+type TruncationConstruct struct {
+	Name    string /* e.g. gene_12-150 for a construct from codon 12 to codon 150 */
+	First   int    /* first codon */
+	Last    int    /* last codon */
+	Forward string /* name of the forward primer */
+	Reverse string /* name of the reverse primer */
+	Protein string /* translation of the codons `First' to `Last' (without added start and stop codons) */
+}
+
+// TruncationSeries holds the primers of a truncation series and the constructs that their pairs amplify
+type TruncationSeries struct {
+	Forward    []TruncationPrimer    /* forward primers in the order of their first codon */
+	Reverse    []TruncationPrimer    /* reverse primers in the order of their last codon */
+	Constructs []TruncationConstruct /* constructs in the order of their first and last codon */
+}
+
+// DesignTruncations designs primers for all constructs of the coding sequence `cds' that start with one of the codons
+// `opts.Starts' and end with one of the codons `opts.Ends' (codons are counted from `opts.Frame'; pairs whose last
+// codon is in front of their first codon are skipped); the forward primers add a start codon and the reverse primers a
+// stop codon if needed; primers are shared by all constructs with the same boundary and primers with identical
+// sequences are only listed once
+func DesignTruncations(cds FastaRecord, opts TruncationOptions) (TruncationSeries, error) {
+	seq, err := ValidateSequence([]byte(cds.Sequence))
+	if err != nil {
+		return TruncationSeries{}, fmt.Errorf("invalid sequence: %v", err)
+	}
+	if opts.Frame == 0 {
+		opts.Frame = 1
+	}
+	if (opts.Frame < 1) || (opts.Frame > len(seq)) {
+		return TruncationSeries{}, fmt.Errorf("invalid input: reading frame start %d, must be between 1 and %d", opts.Frame, len(seq))
+	}
+	protein, err := Translate(seq[opts.Frame-1:])
+	if err != nil {
+		return TruncationSeries{}, err
+	}
+	if cds.Name == "" {
+		cds.Name = "cds"
+	}
+	starts, err := truncationBoundaries(opts.Starts, len(protein))
+	if err != nil {
+		return TruncationSeries{}, err
+	}
+	ends, err := truncationBoundaries(opts.Ends, len(protein))
+	if err != nil {
+		return TruncationSeries{}, err
+	}
+
+	// design one primer per boundary; primers with identical sequences are merged
+	var s TruncationSeries
+	forward, reverse := make(map[int]string), make(map[int]string) /* names of the primers of every boundary */
+	for _, c := range starts {
+		primer, err := FindForward(seq, opts.RestrictF, opts.Frame+(c-1)*Codon, opts.LengthF, opts.RandomF, true)
+		if err != nil {
+			return TruncationSeries{}, fmt.Errorf("cannot design the forward primer of codon %d: %v", c, err)
+		}
+		forward[c] = addTruncationPrimer(&s.Forward, fmt.Sprintf("%s_F%d", cds.Name, c), primer, c)
+	}
+	for _, c := range ends {
+		primer, err := FindReverse(seq, opts.RestrictR, len(seq)-(opts.Frame-1+c*Codon)+1, opts.LengthR, opts.RandomR, true)
+		if err != nil {
+			return TruncationSeries{}, fmt.Errorf("cannot design the reverse primer of codon %d: %v", c, err)
+		}
+		reverse[c] = addTruncationPrimer(&s.Reverse, fmt.Sprintf("%s_R%d", cds.Name, c), primer, c)
+	}
+
+	// every pair of a first and a (later) last codon makes a construct
+	for _, first := range starts {
+		for _, last := range ends {
+			if last < first {
+				continue
+			}
+			s.Constructs = append(s.Constructs, TruncationConstruct{Name: fmt.Sprintf("%s_%d-%d", cds.Name, first, last), First: first, Last: last, Forward: forward[first], Reverse: reverse[last], Protein: protein[first-1 : last]})
+		}
+	}
+	if len(s.Constructs) == 0 {
+		return TruncationSeries{}, fmt.Errorf("invalid input: no last codon is behind a first codon")
+	}
+	return s, nil
+}
+
+// MethylationWarnings returns a warning for every site of `enzymeF' in the forward primers, of `enzymeR' in the reverse
+// primers and of both enzymes in the coding sequence `cds' that overlaps Dam or Dcm methylation which blocks or impairs
+// the enzyme (see `CheckMethylation')
+func (s TruncationSeries) MethylationWarnings(cds string, enzymeF, enzymeR RestrictEnzyme) []string {
+	var warnings []string
+	for _, p := range s.Forward {
+		warnings = append(warnings, methylationWarnings("primer "+p.Name, p.Sequence, enzymeF)...)
+	}
+	for _, p := range s.Reverse {
+		warnings = append(warnings, methylationWarnings("primer "+p.Name, p.Sequence, enzymeR)...)
+	}
+	return append(warnings, methylationWarnings("template", cds, enzymeF, enzymeR)...)
+}
+
+// truncationBoundaries returns the sorted and deduplicated `codons' after checking that they lie between 1 and `n'
+func truncationBoundaries(codons []int, n int) ([]int, error) {
+	if len(codons) == 0 {
+		return nil, fmt.Errorf("invalid input: at least one first and one last codon are required")
+	}
+	seen := make(map[int]bool)
+	var unique []int
+	for _, c := range codons {
+		if (c < 1) || (c > n) {
+			return nil, fmt.Errorf("invalid input: codon %d is outside of the reading frame (1-%d)", c, n)
+		}
+		if !seen[c] {
+			seen[c] = true
+			unique = append(unique, c)
+		}
+	}
+	sort.Ints(unique)
+	return unique, nil
+}
+
+// addTruncationPrimer adds the primer `name' with `sequence' for the boundary `codon' to `primers' and returns its name;
+// if a primer with the same sequence already exists, only the boundary is added to it and its name is returned
+func addTruncationPrimer(primers *[]TruncationPrimer, name, sequence string, codon int) string {
+	for i, p := range *primers {
+		if p.Sequence == sequence {
+			(*primers)[i].Codons = append((*primers)[i].Codons, codon)
+			return p.Name
+		}
+	}
+	*primers = append(*primers, TruncationPrimer{Name: name, Sequence: sequence, Codons: []int{codon}})
+	return name
+}
+
+// WriteMatrix writes the constructs of `s' to `w' as a CSV table with a row per forward primer and a column per
+// reverse primer; every cell gives the name of the construct that the primer pair amplifies (or is empty)
+func (s TruncationSeries) WriteMatrix(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"forward/reverse"}
+	for _, r := range s.Reverse {
+		header = append(header, r.Name)
+	}
+	records := [][]string{header}
+	for _, f := range s.Forward {
+		row := []string{f.Name}
+		for _, r := range s.Reverse {
+			var cell string
+			for _, c := range s.Constructs {
+				if (c.Forward == f.Name) && (c.Reverse == r.Name) {
+					cell = c.Name
+				}
+			}
+			row = append(row, cell)
+		}
+		records = append(records, row)
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("error writing CSV data: %v", err)
+	}
+	return nil
+}
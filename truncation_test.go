@@ -0,0 +1,94 @@
+package cloningprimer
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDesignTruncations(t *testing.T) {
+	repeat := "ATG" + "GCTAAAGAGACCGTT" + "GCTAAAGAGACCGTT" + "TAA" /* codons 2 and 7 start the same forward primer */
+	cases := []struct {
+		seq        string
+		starts     []int
+		ends       []int
+		forward    []TruncationPrimer
+		reverse    []TruncationPrimer
+		constructs []string
+		err        error
+	}{
+		// boundaries are sorted and deduplicated, pairs with the last codon in front of the first codon are skipped
+		{
+			seq:        testCDS,
+			starts:     []int{10, 1, 10},
+			ends:       []int{43, 5},
+			forward:    []TruncationPrimer{{Name: "gene_F1", Sequence: "AGCTGGATCCATGGCTAAAGAGACC", Codons: []int{1}}, {Name: "gene_F10", Sequence: "AGCTGGATCCATGATCGCTGAAGAACTG", Codons: []int{10}}},
+			reverse:    []TruncationPrimer{{Name: "gene_R5", Sequence: "AGCTGAATTCTTAGGTCTCTTTAGCCAT", Codons: []int{5}}, {Name: "gene_R43", Sequence: "AGCTGAATTCTTAGCGTTTCACCAG", Codons: []int{43}}},
+			constructs: []string{"gene_1-5 (gene_F1, gene_R5)", "gene_1-43 (gene_F1, gene_R43)", "gene_10-43 (gene_F10, gene_R43)"},
+			err:        nil,
+		},
+		// primers with identical sequences are shared
+		{
+			seq:        repeat,
+			starts:     []int{2, 7},
+			ends:       []int{12},
+			forward:    []TruncationPrimer{{Name: "gene_F2", Sequence: "AGCTGGATCCATGGCTAAAGAGACCGTT", Codons: []int{2, 7}}},
+			reverse:    []TruncationPrimer{{Name: "gene_R12", Sequence: "AGCTGAATTCTTAAACGGTCTCTTT", Codons: []int{12}}},
+			constructs: []string{"gene_2-12 (gene_F2, gene_R12)", "gene_7-12 (gene_F2, gene_R12)"},
+			err:        nil,
+		},
+		{seq: testCDS, starts: []int{1}, ends: []int{44}, err: errors.New("invalid input: codon 44 is outside of the reading frame (1-43)")},
+		{seq: testCDS, starts: []int{20}, ends: []int{10}, err: errors.New("invalid input: no last codon is behind a first codon")},
+		{seq: testCDS, starts: nil, ends: []int{10}, err: errors.New("invalid input: at least one first and one last codon are required")},
+	}
+	for _, c := range cases {
+		opts := TruncationOptions{Starts: c.starts, Ends: c.ends, RestrictF: "GGATCC", RestrictR: "GAATTC", LengthF: 15, LengthR: 15, RandomF: 4, RandomR: 4}
+		s, err := DesignTruncations(FastaRecord{Name: "gene", Sequence: c.seq}, opts)
+		if (err == nil) != (c.err == nil) || ((err != nil) && (err.Error() != c.err.Error())) {
+			t.Errorf("DesignTruncations(%v, %v) == %v, want %v\n", c.starts, c.ends, err, c.err)
+		}
+		var constructs []string
+		for _, k := range s.Constructs {
+			constructs = append(constructs, k.Name+" ("+k.Forward+", "+k.Reverse+")")
+		}
+		if !reflect.DeepEqual(s.Forward, c.forward) || !reflect.DeepEqual(s.Reverse, c.reverse) || !reflect.DeepEqual(constructs, c.constructs) {
+			t.Errorf("DesignTruncations(%v, %v) == %v, %v, %v, want %v, %v, %v\n", c.starts, c.ends, s.Forward, s.Reverse, constructs, c.forward, c.reverse, c.constructs)
+		}
+	}
+}
+
+func TestTruncationMethylationWarnings(t *testing.T) {
+	// the BclI site (TGATCA) of the forward primers always overlaps Dam methylation, the EcoRI site does not
+	bclI := RestrictEnzyme{Name: "BclI", RecognitionSite: "TGATCA", Dam: MethylationBlocked}
+	s, err := DesignTruncations(FastaRecord{Name: "gene", Sequence: testCDS}, TruncationOptions{Starts: []int{1, 10}, Ends: []int{43}, RestrictF: "TGATCA", RestrictR: "GAATTC", LengthF: 15, LengthR: 15, RandomF: 4, RandomR: 4})
+	if err != nil {
+		t.Fatalf("DesignTruncations() == %v, want <nil>\n", err)
+	}
+	want := []string{
+		"primer gene_F1: BclI site at position 5 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain",
+		"primer gene_F10: BclI site at position 5 overlaps Dam methylation (blocked): prepare the plasmid from a dam- E. coli strain",
+	}
+	if got := s.MethylationWarnings(testCDS, bclI, testEcoRI); !reflect.DeepEqual(got, want) {
+		t.Errorf("MethylationWarnings() == %q, want %q\n", got, want)
+	}
+}
+
+func TestWriteMatrix(t *testing.T) {
+	opts := TruncationOptions{Starts: []int{1, 10}, Ends: []int{5, 43}, RestrictF: "GGATCC", RestrictR: "GAATTC", LengthF: 15, LengthR: 15, RandomF: 4, RandomR: 4}
+	s, err := DesignTruncations(FastaRecord{Name: "gene", Sequence: testCDS}, opts)
+	if err != nil {
+		t.Fatalf("DesignTruncations() == %v, want <nil>\n", err)
+	}
+	want := `forward/reverse,gene_R5,gene_R43
+gene_F1,gene_1-5,gene_1-43
+gene_F10,,gene_10-43
+`
+	var buf bytes.Buffer
+	if err := s.WriteMatrix(&buf); err != nil {
+		t.Fatalf("WriteMatrix() == %v, want <nil>\n", err)
+	}
+	if buf.String() != want {
+		t.Errorf("WriteMatrix() == %q, want %q\n", buf.String(), want)
+	}
+}